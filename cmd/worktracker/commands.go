@@ -0,0 +1,179 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"WorkTrackerAI/internal/ai"
+	"WorkTrackerAI/internal/config"
+	"WorkTrackerAI/internal/storage"
+	"WorkTrackerAI/pkg/logger"
+	"WorkTrackerAI/pkg/models"
+	"WorkTrackerAI/pkg/workday"
+)
+
+// commandCore 命令行子命令共用的组件
+type commandCore struct {
+	configMgr  *config.Manager
+	storageMgr *storage.Manager
+	aiAnalyzer *ai.Analyzer
+}
+
+// commands 命令行子命令列表
+// 用法: WorkTrackerAI.exe <command> [flags]，结果以 JSON 输出到标准输出
+var commands = map[string]func(core *commandCore, args []string) error{
+	"replay": runReplayCommand,
+	"eval":   runEvalCommand,
+}
+
+// standaloneCommands 不使用正式数据的子命令（自行初始化所需组件）
+var standaloneCommands = map[string]func(args []string) error{
+	"simulate": runSimulateCommand,
+}
+
+// isCommand 是否为已知的子命令名称
+func isCommand(name string) bool {
+	_, ok := commands[name]
+	_, standalone := standaloneCommands[name]
+	return ok || standalone
+}
+
+// runCommand 执行命令行子命令，返回进程退出码
+func runCommand(name string, args []string) int {
+	if standalone, ok := standaloneCommands[name]; ok {
+		if err := standalone(args); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %s: %v\n", name, err)
+			return 1
+		}
+		return 0
+	}
+
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "未知命令: %s\n", name)
+		return 2
+	}
+
+	core, err := openCommandCore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ 初始化失败: %v\n", err)
+		return 1
+	}
+	defer core.storageMgr.Close()
+	defer logger.Close()
+
+	if err := cmd(core, args); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %s: %v\n", name, err)
+		return 1
+	}
+	return 0
+}
+
+// openCommandCore 初始化配置、存储与分析器（不启动截屏、调度器和托盘）
+func openCommandCore() (*commandCore, error) {
+	appDataDir := getAppDataDir()
+
+	configMgr, err := config.NewManager(filepath.Join(appDataDir, "data", "config.json"))
+	if err != nil {
+		return nil, fmt.Errorf("初始化配置管理器失败: %w", err)
+	}
+
+	// 日志只写入文件，保证标准输出为纯 JSON
+	storageCfg := configMgr.GetStorage()
+	if err := logger.Init(filepath.Join(storageCfg.DataDir, "logs"), false); err != nil {
+		return nil, fmt.Errorf("初始化日志系统失败: %w", err)
+	}
+
+	storageMgr, err := storage.NewManager(storageCfg.DataDir)
+	if err != nil {
+		return nil, fmt.Errorf("初始化存储管理器失败: %w", err)
+	}
+
+	workday.SetCalendar(storageMgr)
+
+	return &commandCore{
+		configMgr:  configMgr,
+		storageMgr: storageMgr,
+		aiAnalyzer: ai.NewAnalyzer(configMgr, storageMgr),
+	}, nil
+}
+
+// printJSON 以缩进 JSON 输出结果
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// runReplayCommand 重放一次历史分析
+// 用法: replay -id 12 [-mode parse|resend]
+func runReplayCommand(core *commandCore, args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	id := fs.Int64("id", 0, "分析记录 ID")
+	mode := fs.String("mode", ai.ReplayModeParse, "重放模式: parse 仅重新解析, resend 重新发送相同请求")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *id <= 0 {
+		return fmt.Errorf("必须通过 -id 指定分析记录")
+	}
+
+	result, err := core.aiAnalyzer.ReplayRun(*id, *mode)
+	if err != nil {
+		return err
+	}
+	return printJSON(result)
+}
+
+// runEvalCommand 在历史时间段上评测多个提供商/模型/提示词组合
+// 用法: eval -config eval.json
+// 配置文件格式与 POST /api/eval/runs 的请求体一致:
+//
+//	{"name": "...", "periods": [{"start": "...", "end": "..."}], "summary_ids": [1, 2],
+//	 "candidates": [{"label": "...", "provider": "openai", "model": "gpt-4o", "prompt": "..."}]}
+func runEvalCommand(core *commandCore, args []string) error {
+	fs := flag.NewFlagSet("eval", flag.ContinueOnError)
+	configFile := fs.String("config", "", "评测配置文件 (JSON)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *configFile == "" {
+		return fmt.Errorf("必须通过 -config 指定评测配置文件")
+	}
+
+	data, err := os.ReadFile(*configFile)
+	if err != nil {
+		return fmt.Errorf("读取评测配置失败: %w", err)
+	}
+
+	var req struct {
+		Name       string                 `json:"name"`
+		Periods    []models.EvalPeriod    `json:"periods"`
+		SummaryIDs []int64                `json:"summary_ids"`
+		Candidates []models.EvalCandidate `json:"candidates"`
+	}
+	if err := json.Unmarshal(data, &req); err != nil {
+		return fmt.Errorf("解析评测配置失败: %w", err)
+	}
+
+	periods := req.Periods
+	for _, id := range req.SummaryIDs {
+		summary, err := core.storageMgr.GetWorkSummary(id)
+		if err != nil {
+			return err
+		}
+		periods = append(periods, models.EvalPeriod{Start: summary.StartTime, End: summary.EndTime})
+	}
+
+	run, err := core.aiAnalyzer.CreateEvaluation(req.Name, periods, req.Candidates)
+	if err != nil {
+		return err
+	}
+	if err := core.aiAnalyzer.RunEvaluation(run); err != nil {
+		return err
+	}
+	return printJSON(run)
+}
@@ -15,6 +15,7 @@ import (
 	"WorkTrackerAI/internal/storage"
 	"WorkTrackerAI/internal/tray"
 	"WorkTrackerAI/pkg/logger"
+	"WorkTrackerAI/pkg/workday"
 )
 
 const (
@@ -42,14 +43,11 @@ func getAppDataDir() string {
 func main() {
 	// printBanner()
 
-	// 单实例检测 - 防止程序重复启动
-	mutex, err := singleton.EnsureSingleInstance(AppName)
-	if err != nil {
-		// 已有实例在运行，退出
-		os.Exit(1)
+	// 命令行子命令（如 replay）：执行后直接退出，不启动托盘
+	// 其他参数（如启动器或服务附加的参数）忽略，正常启动
+	if len(os.Args) > 1 && isCommand(os.Args[1]) {
+		os.Exit(runCommand(os.Args[1], os.Args[2:]))
 	}
-	// 确保程序退出时释放互斥锁
-	defer mutex.Close()
 
 	// 获取应用数据目录
 	appDataDir := getAppDataDir()
@@ -59,6 +57,15 @@ func main() {
 		log.Fatalf("❌ 创建应用数据目录失败 %s: %v", appDataDir, err)
 	}
 
+	// 单实例检测 - 防止程序重复启动
+	mutex, err := singleton.EnsureSingleInstance(AppName, appDataDir)
+	if err != nil {
+		// 已有实例在运行，退出
+		os.Exit(1)
+	}
+	// 确保程序退出时释放互斥锁
+	defer mutex.Close()
+
 	// 初始化配置管理器
 	configPath := filepath.Join(appDataDir, "data", "config.json")
 	configMgr, err := config.NewManager(configPath)
@@ -100,6 +107,9 @@ func main() {
 	}
 	fmt.Println("✅ 存储管理器初始化完成")
 
+	// 工作日判断使用数据库中的节假日日历（放假、调休上班）
+	workday.SetCalendar(storageMgr)
+
 	// 初始化截屏引擎
 	captureEng := capture.NewEngine(configMgr, storageMgr)
 	fmt.Println("✅ 截屏引擎初始化完成")
@@ -115,7 +125,7 @@ func main() {
 	}
 
 	// 初始化 Web 服务器
-	webServer := server.NewServer(configMgr, storageMgr, captureEng, aiAnalyzer, AppVersion)
+	webServer := server.NewServer(configMgr, storageMgr, captureEng, aiAnalyzer, sched, AppVersion)
 
 	// 启动 Web 服务器（在独立 goroutine 中）
 	go func() {
@@ -0,0 +1,469 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"WorkTrackerAI/internal/ai"
+	"WorkTrackerAI/internal/config"
+	"WorkTrackerAI/internal/scheduler"
+	"WorkTrackerAI/internal/storage"
+	"WorkTrackerAI/pkg/clock"
+	"WorkTrackerAI/pkg/imageformat"
+	"WorkTrackerAI/pkg/logger"
+	"WorkTrackerAI/pkg/models"
+	"WorkTrackerAI/pkg/workday"
+)
+
+// simulationResult 模拟运行结果
+type simulationResult struct {
+	DataDir     string                `json:"data_dir"`
+	Start       time.Time             `json:"start"`
+	End         time.Time             `json:"end"`
+	Elapsed     string                `json:"elapsed"`
+	Screenshots int                   `json:"screenshots"`
+	Summaries   []*models.WorkSummary `json:"summaries"`
+	JobRuns     []*models.JobRun      `json:"job_runs"`
+	Backlog     []*models.BacklogItem `json:"backlog"`
+}
+
+// simulationOptions 模拟运行参数
+type simulationOptions struct {
+	imagesDir   string
+	date        string
+	days        int
+	interval    time.Duration
+	step        time.Duration
+	speed       float64
+	dataDir     string
+	llmEndpoint string
+	stubLLM     bool
+}
+
+// runSimulateCommand 使用模拟时钟回放图片文件夹，无界面运行调度与分析
+// 用法: simulate -images ./samples [-date 2026-10-19] [-days 1] [-interval 60] [-step 30s] [-speed 0]
+//
+//	[-data ./sim] [-llm http://127.0.0.1:8080/v1/chat/completions | -stub-llm]
+//
+// 图片按文件名顺序循环作为截图，每 interval 秒（模拟时间）一张，只在截图引擎运行且处于工作时间内时保存。
+// 模拟数据写入独立的数据目录，不影响正式数据；配置以正式配置为基础。
+func runSimulateCommand(args []string) error {
+	fs := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	imagesDir := fs.String("images", "", "用作截图的图片文件夹")
+	date := fs.String("date", "", "模拟的第一个逻辑日 (2006-01-02)，默认今天")
+	days := fs.Int("days", 1, "模拟天数")
+	interval := fs.Int("interval", 60, "模拟截图间隔（秒）")
+	step := fs.Duration("step", 30*time.Second, "每次推进的模拟时长")
+	speed := fs.Float64("speed", 0, "相对真实时间的倍速，0 表示尽快运行")
+	dataDir := fs.String("data", "", "模拟数据目录，默认在临时目录下新建")
+	llmEndpoint := fs.String("llm", "", "替代的 LLM 对话接口地址（OpenAI 兼容）")
+	stubLLM := fs.Bool("stub-llm", false, "启动内置的模拟 LLM 服务，返回固定格式的分析结果")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *imagesDir == "" {
+		return fmt.Errorf("必须通过 -images 指定图片文件夹")
+	}
+	if *interval <= 0 || *step <= 0 || *days <= 0 {
+		return fmt.Errorf("interval、step 与 days 必须大于 0")
+	}
+
+	// 运行过程中的输出写到标准错误，保证标准输出为纯 JSON
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+	result, err := simulate(simulationOptions{
+		imagesDir:   *imagesDir,
+		date:        *date,
+		days:        *days,
+		interval:    time.Duration(*interval) * time.Second,
+		step:        *step,
+		speed:       *speed,
+		dataDir:     *dataDir,
+		llmEndpoint: *llmEndpoint,
+		stubLLM:     *stubLLM,
+	})
+	os.Stdout = stdout
+	if err != nil {
+		return err
+	}
+	return printJSON(result)
+}
+
+// simulate 执行模拟运行
+func simulate(opts simulationOptions) (*simulationResult, error) {
+	images, err := listImages(opts.imagesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.dataDir == "" {
+		opts.dataDir, err = os.MkdirTemp("", "worktracker-sim-")
+		if err != nil {
+			return nil, fmt.Errorf("创建模拟数据目录失败: %w", err)
+		}
+	}
+
+	endpoint := opts.llmEndpoint
+	if opts.stubLLM {
+		stub, err := startStubLLM()
+		if err != nil {
+			return nil, err
+		}
+		defer stub.Close()
+		endpoint = fmt.Sprintf("http://%s/v1/chat/completions", stub.Addr())
+	}
+
+	configMgr, err := openSimulationConfig(opts.dataDir, endpoint, opts.stubLLM)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := logger.Init(filepath.Join(opts.dataDir, "logs"), false); err != nil {
+		return nil, fmt.Errorf("初始化日志系统失败: %w", err)
+	}
+	defer logger.Close()
+
+	storageMgr, err := storage.NewManager(opts.dataDir)
+	if err != nil {
+		return nil, fmt.Errorf("初始化存储管理器失败: %w", err)
+	}
+	defer storageMgr.Close()
+	workday.SetCalendar(storageMgr)
+
+	// 确定模拟范围（逻辑日）
+	schedule := configMgr.GetSchedule()
+	first := workday.LogicalDay(schedule, time.Now())
+	if opts.date != "" {
+		first, err = time.ParseInLocation("2006-01-02", opts.date, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("无效的日期: %s", opts.date)
+		}
+	}
+	start := workday.DayRange(schedule, first).Start
+	end := workday.DayRange(schedule, first.AddDate(0, 0, opts.days-1)).End
+
+	fake := clock.NewFake(start)
+	clock.Set(fake)
+	defer clock.Set(nil)
+
+	capture := &simCapture{
+		images:     images,
+		interval:   opts.interval,
+		dataDir:    opts.dataDir,
+		configMgr:  configMgr,
+		storageMgr: storageMgr,
+	}
+	sched := scheduler.NewScheduler(configMgr, storageMgr, ai.NewAnalyzer(configMgr, storageMgr), capture)
+	if err := sched.StartManual(); err != nil {
+		return nil, err
+	}
+	defer sched.Stop()
+
+	fmt.Fprintf(os.Stderr, "▶️ 模拟运行 %s - %s (%d 张图片，数据目录 %s)\n",
+		start.Format("2006-01-02 15:04"), end.Format("2006-01-02 15:04"), len(images), opts.dataDir)
+
+	began := time.Now()
+	for now := start; now.Before(end); {
+		now = fake.Advance(opts.step)
+		if err := capture.captureUntil(now); err != nil {
+			return nil, err
+		}
+		sched.RunDue()
+		if opts.speed > 0 {
+			time.Sleep(time.Duration(float64(opts.step) / opts.speed))
+		}
+	}
+
+	result := &simulationResult{
+		DataDir: opts.dataDir,
+		Start:   start,
+		End:     end,
+		Elapsed: time.Since(began).Round(time.Millisecond).String(),
+	}
+	if result.Screenshots, err = storageMgr.CountScreenshots(start, end); err != nil {
+		return nil, err
+	}
+	if result.Summaries, err = storageMgr.GetWorkSummaries(start, end); err != nil {
+		return nil, err
+	}
+	if result.JobRuns, err = storageMgr.GetJobRuns("", 1000); err != nil {
+		return nil, err
+	}
+	if result.Backlog, err = storageMgr.GetPendingBacklog(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// listImages 列出文件夹中的图片（按文件名排序）
+func listImages(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("读取图片文件夹失败: %w", err)
+	}
+
+	var images []string
+	for _, entry := range entries {
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".jpg", ".jpeg", ".png", ".webp":
+			images = append(images, filepath.Join(dir, entry.Name()))
+		}
+	}
+	if len(images) == 0 {
+		return nil, fmt.Errorf("文件夹中没有图片: %s", dir)
+	}
+	sort.Strings(images)
+	return images, nil
+}
+
+// openSimulationConfig 创建模拟使用的配置：以正式配置为基础，数据目录指向模拟目录
+func openSimulationConfig(dataDir, llmEndpoint string, stub bool) (*config.Manager, error) {
+	configPath := filepath.Join(dataDir, "config.json")
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		if data, err := os.ReadFile(filepath.Join(getAppDataDir(), "data", "config.json")); err == nil {
+			if err := os.WriteFile(configPath, data, 0644); err != nil {
+				return nil, fmt.Errorf("复制正式配置失败: %w", err)
+			}
+		}
+	}
+
+	configMgr, err := config.NewManager(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("初始化配置管理器失败: %w", err)
+	}
+
+	err = configMgr.Update(func(cfg *models.AppConfig) {
+		cfg.Capture.Enabled = true
+		cfg.Storage.DataDir = dataDir
+		cfg.Storage.ScreenshotsDir = filepath.Join(dataDir, "screenshots")
+		cfg.Storage.LogsDir = filepath.Join(dataDir, "logs")
+		if llmEndpoint != "" {
+			cfg.AI.Endpoint = llmEndpoint
+		}
+		if stub {
+			cfg.AI.Provider = "openai"
+			cfg.AI.APIKey = "simulation"
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("更新模拟配置失败: %w", err)
+	}
+	return configMgr, nil
+}
+
+// simCapture 模拟截图引擎：按模拟时间循环使用图片文件夹中的图片作为截图
+type simCapture struct {
+	images     []string
+	interval   time.Duration
+	dataDir    string
+	configMgr  *config.Manager
+	storageMgr *storage.Manager
+
+	mu      sync.Mutex
+	running bool
+	next    time.Time // 下一次截图的模拟时间
+	index   int       // 下一张使用的图片
+}
+
+func (c *simCapture) Start() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.running {
+		return fmt.Errorf("capture engine already running")
+	}
+	c.running = true
+	c.next = clock.Now().Add(c.interval)
+	return nil
+}
+
+func (c *simCapture) Stop() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.running {
+		return fmt.Errorf("capture engine not running")
+	}
+	c.running = false
+	return nil
+}
+
+func (c *simCapture) IsRunning() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.running
+}
+
+// captureUntil 保存到 now 为止所有到期的截图
+func (c *simCapture) captureUntil(now time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for c.running && !c.next.After(now) {
+		at := c.next
+		c.next = c.next.Add(c.interval)
+
+		schedule := c.configMgr.GetSchedule()
+		if schedule.Enabled && !workday.InWorkTime(schedule, at) {
+			continue
+		}
+		if err := c.save(at); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// save 将下一张图片复制到截图目录并写入数据库
+func (c *simCapture) save(at time.Time) error {
+	src := c.images[c.index%len(c.images)]
+	c.index++
+
+	dir := filepath.Join(c.dataDir, "screenshots", at.Format("2006-01-02"))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	dst := filepath.Join(dir, fmt.Sprintf("screenshot_sim_%s%s", at.Format("20060102_150405"), filepath.Ext(src)))
+
+	size, err := copyFile(src, dst)
+	if err != nil {
+		return err
+	}
+
+	resolution := ""
+	if f, err := os.Open(dst); err == nil {
+		if cfg, _, err := image.DecodeConfig(f); err == nil {
+			resolution = fmt.Sprintf("%dx%d", cfg.Width, cfg.Height)
+		}
+		f.Close()
+	}
+
+	return c.storageMgr.SaveScreenshot(&models.Screenshot{
+		Timestamp:   at,
+		ScreenIndex: -1,
+		FilePath:    dst,
+		FileSize:    size,
+		Resolution:  resolution,
+		Format:      imageformat.FromPath(dst),
+		CreatedAt:   at,
+	})
+}
+
+// copyFile 复制文件，返回写入的字节数
+func copyFile(src, dst string) (int64, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open image: %w", err)
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create file: %w", err)
+	}
+	defer out.Close()
+
+	n, err := io.Copy(out, in)
+	if err != nil {
+		return 0, fmt.Errorf("failed to copy image: %w", err)
+	}
+	return n, nil
+}
+
+// stubTimePattern 从提示词中提取时间段（"15:04"）
+var stubTimePattern = regexp.MustCompile(`\b(\d{2}):(\d{2})\b`)
+
+// stubLLM 内置的模拟 LLM 服务（OpenAI 兼容），按请求中的图片数量与时间段返回固定格式的分析结果
+type stubLLM struct {
+	listener net.Listener
+	server   *http.Server
+}
+
+// startStubLLM 在本机随机端口启动模拟 LLM 服务
+func startStubLLM() (*stubLLM, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("启动模拟 LLM 服务失败: %w", err)
+	}
+
+	stub := &stubLLM{listener: listener}
+	stub.server = &http.Server{Handler: http.HandlerFunc(stub.handleChat)}
+	go stub.server.Serve(listener)
+	return stub, nil
+}
+
+// Addr 服务监听地址
+func (s *stubLLM) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Close 关闭服务
+func (s *stubLLM) Close() error {
+	return s.server.Close()
+}
+
+// handleChat 处理对话请求
+func (s *stubLLM) handleChat(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Messages []struct {
+			Content []map[string]interface{} `json:"content"`
+		} `json:"messages"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	images, prompt := 0, ""
+	for _, msg := range req.Messages {
+		for _, part := range msg.Content {
+			switch part["type"] {
+			case "image_url":
+				images++
+			case "text":
+				if text, ok := part["text"].(string); ok {
+					prompt += text
+				}
+			}
+		}
+	}
+
+	// 活动时长取提示词中前两个时间点之间的分钟数
+	minutes := 0
+	if times := stubTimePattern.FindAllStringSubmatch(prompt, 2); len(times) == 2 {
+		var h1, m1, h2, m2 int
+		fmt.Sscanf(times[0][1]+" "+times[0][2], "%d %d", &h1, &m1)
+		fmt.Sscanf(times[1][1]+" "+times[1][2], "%d %d", &h2, &m2)
+		minutes = (h2*60 + m2) - (h1*60 + m1)
+		if minutes < 0 {
+			minutes += 24 * 60
+		}
+	}
+
+	content, _ := json.Marshal(map[string]interface{}{
+		"summary": fmt.Sprintf("模拟分析：共 %d 张截图", images),
+		"activities": []map[string]interface{}{
+			{"name": "模拟工作", "duration_minutes": minutes, "apps": []string{"Simulator"}, "category": "work"},
+		},
+		"app_usage": map[string]int{"Simulator": minutes},
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"choices": []map[string]interface{}{
+			{"message": map[string]string{"role": "assistant", "content": string(content)}},
+		},
+	})
+}
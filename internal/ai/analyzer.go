@@ -5,9 +5,12 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -15,15 +18,34 @@ import (
 
 	"WorkTrackerAI/internal/config"
 	"WorkTrackerAI/internal/storage"
+	"WorkTrackerAI/pkg/appusage"
+	"WorkTrackerAI/pkg/capturegap"
+	"WorkTrackerAI/pkg/clock"
+	"WorkTrackerAI/pkg/imageformat"
 	"WorkTrackerAI/pkg/logger"
 	"WorkTrackerAI/pkg/models"
 )
 
+var (
+	// ErrNoScreenshots 时间段内没有截图，重试也无法分析
+	ErrNoScreenshots = errors.New("未找到截图数据")
+	// ErrCallFailed 调用 LLM 失败（服务不可达、HTTP 错误等）
+	ErrCallFailed = errors.New("failed to call LLM")
+	// ErrParseFailed LLM 响应无法解析
+	ErrParseFailed = errors.New("failed to parse response")
+)
+
+// Retryable 分析失败是否为临时性错误（LLM 调用或响应解析失败），稍后重试可能成功
+func Retryable(err error) bool {
+	return errors.Is(err, ErrCallFailed) || errors.Is(err, ErrParseFailed)
+}
+
 // Analyzer AI 分析器
 type Analyzer struct {
 	configMgr *config.Manager
 	storage   *storage.Manager
 	client    *http.Client
+	claims    *segmentClaims
 }
 
 // NewAnalyzer 创建 AI 分析器
@@ -34,6 +56,7 @@ func NewAnalyzer(configMgr *config.Manager, storageMgr *storage.Manager) *Analyz
 		client: &http.Client{
 			Timeout: 2 * time.Minute,
 		},
+		claims: newSegmentClaims(),
 	}
 }
 
@@ -53,23 +76,57 @@ func (a *Analyzer) AnalyzePeriod(start, end time.Time) (*models.WorkSummary, err
 
 	if len(screenshots) == 0 {
 		logger.Warn("时间段内没有截图数据")
-		return nil, fmt.Errorf("未找到截图数据，请先点击'开始截屏'采集数据后再进行分析")
+		return nil, fmt.Errorf("%w，请先点击'开始截屏'采集数据后再进行分析", ErrNoScreenshots)
 	}
 
-	// 2. 智能采样
+	// 2. 智能采样（无变化心跳与原截图是同一文件，只保留一张）
 	logger.Info("步骤2: 智能采样...")
+	frames := uniqueFrames(screenshots)
+	if len(frames) < len(screenshots) {
+		logger.Info("去除无变化画面后数量: %d", len(frames))
+	}
 	maxImages := a.configMgr.GetAI().MaxImages
-	sampled := a.sampleScreenshots(screenshots, maxImages)
+	sampled := a.sampleScreenshots(frames, maxImages)
 	logger.Info("采样后数量: %d (最大: %d)", len(sampled), maxImages)
 
 	// 3. 调用 LLM 分析
-	logger.Info("步骤3: 调用AI分析 (提供商: %s, 模型: %s)...",
-		a.configMgr.GetAI().Provider, a.configMgr.GetAI().Model)
-	aiResponse, err := a.callLLM(sampled, start, end)
+	aiCfg := a.configMgr.GetAI()
+	logger.Info("步骤3: 调用AI分析 (提供商: %s, 模型: %s)...", aiCfg.Provider, aiCfg.Model)
+	prompt := a.buildPrompt(start, end)
+
+	// 先读取并遮挡图片，提示词中的图片序号只对应实际发送的截图
+	images := a.prepareImages(sampled)
+	requestScreenshots := imageShots(images)
+
+	// 截图描述模式：时段内已有足够的描述时只发送文字，否则要求模型逐张描述
+	var reusedCaptions []*models.ScreenshotCaption
+	if aiCfg.CaptionMode {
+		reusedCaptions = a.reusableCaptions(start, end, len(requestScreenshots))
+		if reusedCaptions != nil {
+			logger.Info("复用已有截图描述 %d 条，不再发送图片", len(reusedCaptions))
+			prompt += buildCaptionContextSection(reusedCaptions)
+			images, requestScreenshots = nil, nil
+		} else {
+			prompt += buildCaptionRequestSection(requestScreenshots)
+		}
+	}
+	prompt += a.buildWindowSection(start, end, requestScreenshots)
+	prompt += a.buildGapSection(start, end)
+	prompt += a.buildRedactionSection()
+
+	run := newAnalysisRun(start, end, aiCfg, prompt)
+	callStart := time.Now()
+	aiResponse, sentIDs, err := a.callLLM(aiCfg, prompt, images)
+	run.LatencyMs = time.Since(callStart).Milliseconds()
+	run.ScreenshotIDs = sentIDs
 	if err != nil {
 		logger.Error("AI分析失败: %v", err)
-		return nil, fmt.Errorf("failed to call LLM: %w", err)
+		run.ParseStatus = models.ParseStatusCallFailed
+		run.Error = err.Error()
+		a.saveRun(run)
+		return nil, fmt.Errorf("%w: %w", ErrCallFailed, err)
 	}
+	run.RawResponse = aiResponse
 	logger.Info("AI返回成功，响应长度: %d 字符", len(aiResponse))
 	logger.Info("========== AI原始返回 ==========")
 	logger.Info("%s", aiResponse)
@@ -81,17 +138,37 @@ func (a *Analyzer) AnalyzePeriod(start, end time.Time) (*models.WorkSummary, err
 	if err != nil {
 		logger.Error("解析响应失败: %v", err)
 		logger.Error("原始响应内容: %s", aiResponse)
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+		run.ParseStatus = models.ParseStatusParseFailed
+		run.Error = err.Error()
+		a.saveRun(run)
+		return nil, fmt.Errorf("%w: %w", ErrParseFailed, err)
 	}
 	logger.Info("解析成功: 活动数=%d, 应用数=%d", len(summary.Activities), len(summary.AppUsage))
+	run.ParseStatus = models.ParseStatusOK
+
+	// 应用使用时长以前台窗口采样的实测值为准，AI 的结果只用于活动命名
+	usage := a.measureAppUsage(start, end)
+	if usage != nil {
+		summary.AppUsage = appusage.Minutes(usage)
+		logger.Info("使用实测应用时长: 应用数=%d, 采样数=%d", len(summary.AppUsage), usage.Samples)
+	}
 
 	// 5. 保存总结到数据库
 	logger.Info("步骤5: 保存到数据库...")
 	if err := a.storage.SaveWorkSummary(summary); err != nil {
 		logger.Error("保存到数据库失败: %v", err)
+		run.Error = err.Error()
+		a.saveRun(run)
 		return nil, fmt.Errorf("failed to save summary: %w", err)
 	}
 	logger.Info("数据库保存成功")
+	run.SummaryID = summary.ID
+	a.saveRun(run)
+	a.saveAppUsage(summary.ID, usage)
+
+	if aiCfg.CaptionMode && reusedCaptions == nil {
+		a.saveCaptions(aiResponse, sentIDs, run)
+	}
 
 	// 6. 保存总结到本地Markdown文件
 	logger.Info("步骤6: 保存到Markdown文件...")
@@ -114,6 +191,21 @@ func (a *Analyzer) AnalyzePeriod(start, end time.Time) (*models.WorkSummary, err
 	return summary, nil
 }
 
+// uniqueFrames 按文件去重，保留每个文件最早出现的记录
+// 原截图在时间段之前时，保留时间段内第一条引用它的心跳记录
+func uniqueFrames(all []*models.Screenshot) []*models.Screenshot {
+	seen := make(map[string]bool, len(all))
+	frames := make([]*models.Screenshot, 0, len(all))
+	for _, ss := range all {
+		if seen[ss.FilePath] {
+			continue
+		}
+		seen[ss.FilePath] = true
+		frames = append(frames, ss)
+	}
+	return frames
+}
+
 // sampleScreenshots 智能采样截图
 func (a *Analyzer) sampleScreenshots(all []*models.Screenshot, maxCount int) []*models.Screenshot {
 	if len(all) <= maxCount {
@@ -134,24 +226,138 @@ func (a *Analyzer) sampleScreenshots(all []*models.Screenshot, maxCount int) []*
 	return sampled
 }
 
-// callLLM 调用大语言模型
-func (a *Analyzer) callLLM(screenshots []*models.Screenshot, start, end time.Time) (string, error) {
-	cfg := a.configMgr.GetAI()
+// callLLM 调用大语言模型，返回响应内容以及实际发送的截图 ID
+func (a *Analyzer) callLLM(cfg models.AIConfig, prompt string, uploads []uploadImage) (string, []int64, error) {
+	images, sentIDs := imageContents(uploads)
 
+	var response string
+	var err error
 	switch cfg.Provider {
 	case "openai":
-		return a.callOpenAI(screenshots, start, end, cfg)
+		response, err = a.callOpenAI(prompt, images, cfg)
 	case "claude":
-		return a.callClaude(screenshots, start, end, cfg)
+		response, err = a.callClaude(prompt, images, cfg)
 	case "deepseek":
-		return a.callDeepSeek(screenshots, start, end, cfg)
+		response, err = a.callDeepSeek(prompt, images, cfg)
 	case "qwen", "tongyi":
-		return a.callQwen(screenshots, start, end, cfg)
+		response, err = a.callQwen(prompt, images, cfg)
 	case "doubao":
-		return a.callDoubao(screenshots, start, end, cfg)
+		response, err = a.callDoubao(prompt, images, cfg)
 	default:
-		return "", fmt.Errorf("unsupported AI provider: %s", cfg.Provider)
+		err = fmt.Errorf("unsupported AI provider: %s", cfg.Provider)
+	}
+	return response, sentIDs, err
+}
+
+// defaultChatEndpoints 各提供商默认的对话接口地址
+var defaultChatEndpoints = map[string]string{
+	"openai":   "https://api.openai.com/v1/chat/completions",
+	"deepseek": "https://api.deepseek.com/v1/chat/completions",
+	"qwen":     "https://dashscope.aliyuncs.com/compatible-mode/v1/chat/completions",
+	"tongyi":   "https://dashscope.aliyuncs.com/compatible-mode/v1/chat/completions",
+	"doubao":   "https://ark.cn-beijing.volces.com/api/v3/chat/completions",
+}
+
+// chatEndpoint 返回对话接口地址（优先使用自定义端点）
+func chatEndpoint(cfg models.AIConfig) string {
+	if cfg.Endpoint != "" {
+		return cfg.Endpoint
+	}
+	return defaultChatEndpoints[cfg.Provider]
+}
+
+// CheckReachable 检查当前配置的 AI 服务是否可以连接（仅建立 TCP 连接，不消耗调用额度）
+func (a *Analyzer) CheckReachable() error {
+	endpoint := chatEndpoint(a.configMgr.GetAI())
+	if endpoint == "" {
+		return fmt.Errorf("no endpoint configured")
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid endpoint: %w", err)
+	}
+
+	host := u.Host
+	if u.Port() == "" {
+		if u.Scheme == "http" {
+			host = net.JoinHostPort(u.Hostname(), "80")
+		} else {
+			host = net.JoinHostPort(u.Hostname(), "443")
+		}
+	}
+
+	conn, err := net.DialTimeout("tcp", host, 5*time.Second)
+	if err != nil {
+		return err
+	}
+	conn.Close()
+	return nil
+}
+
+// uploadImage 已读取并按隐私配置遮挡、准备发送给 AI 的截图
+type uploadImage struct {
+	shot     *models.Screenshot
+	data     []byte
+	mimeType string
+}
+
+// prepareImages 读取截图文件并按隐私配置遮挡，本地文件保持不变
+// 读取或遮挡失败的截图会被跳过，提示词中的图片序号应按返回结果编排
+func (a *Analyzer) prepareImages(screenshots []*models.Screenshot) []uploadImage {
+	images := make([]uploadImage, 0, len(screenshots))
+	redactor := a.newUploadRedactor(screenshots)
+
+	for _, ss := range screenshots {
+		imageData, err := os.ReadFile(ss.FilePath)
+		if err != nil {
+			logger.Warn("读取截图失败，跳过: %s (%v)", ss.FilePath, err)
+			continue
+		}
+
+		mimeType := imageformat.MIMEType(ss.Format, ss.FilePath)
+		regions, ok := redactor.regions(ss)
+		if !ok {
+			logger.Warn("截图 #%d 的前台窗口需要遮挡但位置未知，跳过", ss.ID)
+			continue
+		}
+		if len(regions) > 0 {
+			imageData, mimeType, err = redactor.apply(imageData, ss, regions)
+			if err != nil {
+				logger.Warn("遮挡截图失败，跳过: %s (%v)", ss.FilePath, err)
+				continue
+			}
+		}
+
+		images = append(images, uploadImage{shot: ss, data: imageData, mimeType: mimeType})
+	}
+
+	return images
+}
+
+// imageShots 返回待发送图片对应的截图（顺序与图片序号一致）
+func imageShots(images []uploadImage) []*models.Screenshot {
+	shots := make([]*models.Screenshot, len(images))
+	for i, img := range images {
+		shots[i] = img.shot
+	}
+	return shots
+}
+
+// imageContents 将待发送图片编码为图片消息内容，并返回对应的截图 ID
+func imageContents(images []uploadImage) ([]interface{}, []int64) {
+	contents := make([]interface{}, 0, len(images))
+	sentIDs := make([]int64, 0, len(images))
+	for _, img := range images {
+		contents = append(contents, openAIImageContent{
+			Type: "image_url",
+			ImageURL: openAIImageURL{
+				URL: fmt.Sprintf("data:%s;base64,%s", img.mimeType, base64.StdEncoding.EncodeToString(img.data)),
+			},
+		})
+		sentIDs = append(sentIDs, img.shot.ID)
 	}
+	return contents, sentIDs
 }
 
 // OpenAI 请求结构
@@ -191,30 +397,15 @@ type openAIResponse struct {
 }
 
 // callOpenAI 调用 OpenAI API
-func (a *Analyzer) callOpenAI(screenshots []*models.Screenshot, start, end time.Time, cfg models.AIConfig) (string, error) {
+func (a *Analyzer) callOpenAI(prompt string, images []interface{}, cfg models.AIConfig) (string, error) {
 	// 构建消息内容
 	content := []interface{}{
 		openAITextContent{
 			Type: "text",
-			Text: a.buildPrompt(start, end),
+			Text: prompt,
 		},
 	}
-
-	// 添加图片
-	for _, ss := range screenshots {
-		imageData, err := os.ReadFile(ss.FilePath)
-		if err != nil {
-			continue
-		}
-
-		base64Image := base64.StdEncoding.EncodeToString(imageData)
-		content = append(content, openAIImageContent{
-			Type: "image_url",
-			ImageURL: openAIImageURL{
-				URL: fmt.Sprintf("data:image/jpeg;base64,%s", base64Image),
-			},
-		})
-	}
+	content = append(content, images...)
 
 	// 构建请求
 	reqBody := openAIRequest{
@@ -244,10 +435,7 @@ func (a *Analyzer) callOpenAI(screenshots []*models.Screenshot, start, end time.
 	}
 
 	// 发送请求
-	endpoint := "https://api.openai.com/v1/chat/completions"
-	if cfg.Endpoint != "" {
-		endpoint = cfg.Endpoint
-	}
+	endpoint := chatEndpoint(cfg)
 
 	req, err := http.NewRequestWithContext(context.Background(), "POST", endpoint, bytes.NewBuffer(jsonData))
 	if err != nil {
@@ -282,38 +470,23 @@ func (a *Analyzer) callOpenAI(screenshots []*models.Screenshot, start, end time.
 }
 
 // callClaude 调用 Claude API
-func (a *Analyzer) callClaude(screenshots []*models.Screenshot, start, end time.Time, cfg models.AIConfig) (string, error) {
+func (a *Analyzer) callClaude(prompt string, images []interface{}, cfg models.AIConfig) (string, error) {
 	// Claude API 实现（类似 OpenAI，但结构略有不同）
 	return "", fmt.Errorf("Claude API not implemented yet")
 }
 
 // callDeepSeek 调用 DeepSeek API
 // DeepSeek API 兼容 OpenAI 格式
-func (a *Analyzer) callDeepSeek(screenshots []*models.Screenshot, start, end time.Time, cfg models.AIConfig) (string, error) {
+func (a *Analyzer) callDeepSeek(prompt string, images []interface{}, cfg models.AIConfig) (string, error) {
 	// DeepSeek 使用与 OpenAI 相同的 API 格式
 	// 构建消息内容
 	content := []interface{}{
 		openAITextContent{
 			Type: "text",
-			Text: a.buildPrompt(start, end),
+			Text: prompt,
 		},
 	}
-
-	// 添加图片
-	for _, ss := range screenshots {
-		imageData, err := os.ReadFile(ss.FilePath)
-		if err != nil {
-			continue
-		}
-
-		base64Image := base64.StdEncoding.EncodeToString(imageData)
-		content = append(content, openAIImageContent{
-			Type: "image_url",
-			ImageURL: openAIImageURL{
-				URL: fmt.Sprintf("data:image/jpeg;base64,%s", base64Image),
-			},
-		})
-	}
+	content = append(content, images...)
 
 	// 构建请求
 	reqBody := openAIRequest{
@@ -343,10 +516,7 @@ func (a *Analyzer) callDeepSeek(screenshots []*models.Screenshot, start, end tim
 	}
 
 	// DeepSeek API 端点
-	endpoint := "https://api.deepseek.com/v1/chat/completions"
-	if cfg.Endpoint != "" {
-		endpoint = cfg.Endpoint
-	}
+	endpoint := chatEndpoint(cfg)
 
 	req, err := http.NewRequestWithContext(context.Background(), "POST", endpoint, bytes.NewBuffer(jsonData))
 	if err != nil {
@@ -381,31 +551,16 @@ func (a *Analyzer) callDeepSeek(screenshots []*models.Screenshot, start, end tim
 }
 
 // callQwen 调用通义千问 API
-func (a *Analyzer) callQwen(screenshots []*models.Screenshot, start, end time.Time, cfg models.AIConfig) (string, error) {
+func (a *Analyzer) callQwen(prompt string, images []interface{}, cfg models.AIConfig) (string, error) {
 	// 通义千问（阿里云）API 实现
 	// 也兼容 OpenAI 格式
 	content := []interface{}{
 		openAITextContent{
 			Type: "text",
-			Text: a.buildPrompt(start, end),
+			Text: prompt,
 		},
 	}
-
-	// 添加图片
-	for _, ss := range screenshots {
-		imageData, err := os.ReadFile(ss.FilePath)
-		if err != nil {
-			continue
-		}
-
-		base64Image := base64.StdEncoding.EncodeToString(imageData)
-		content = append(content, openAIImageContent{
-			Type: "image_url",
-			ImageURL: openAIImageURL{
-				URL: fmt.Sprintf("data:image/jpeg;base64,%s", base64Image),
-			},
-		})
-	}
+	content = append(content, images...)
 
 	// 构建请求
 	reqBody := openAIRequest{
@@ -435,10 +590,7 @@ func (a *Analyzer) callQwen(screenshots []*models.Screenshot, start, end time.Ti
 	}
 
 	// 通义千问 API 端点
-	endpoint := "https://dashscope.aliyuncs.com/compatible-mode/v1/chat/completions"
-	if cfg.Endpoint != "" {
-		endpoint = cfg.Endpoint
-	}
+	endpoint := chatEndpoint(cfg)
 
 	req, err := http.NewRequestWithContext(context.Background(), "POST", endpoint, bytes.NewBuffer(jsonData))
 	if err != nil {
@@ -473,31 +625,16 @@ func (a *Analyzer) callQwen(screenshots []*models.Screenshot, start, end time.Ti
 }
 
 // callDoubao 调用豆包 API
-func (a *Analyzer) callDoubao(screenshots []*models.Screenshot, start, end time.Time, cfg models.AIConfig) (string, error) {
+func (a *Analyzer) callDoubao(prompt string, images []interface{}, cfg models.AIConfig) (string, error) {
 	// 豆包（字节跳动）API 实现
 	// 也兼容 OpenAI 格式
 	content := []interface{}{
 		openAITextContent{
 			Type: "text",
-			Text: a.buildPrompt(start, end),
+			Text: prompt,
 		},
 	}
-
-	// 添加图片
-	for _, ss := range screenshots {
-		imageData, err := os.ReadFile(ss.FilePath)
-		if err != nil {
-			continue
-		}
-
-		base64Image := base64.StdEncoding.EncodeToString(imageData)
-		content = append(content, openAIImageContent{
-			Type: "image_url",
-			ImageURL: openAIImageURL{
-				URL: fmt.Sprintf("data:image/jpeg;base64,%s", base64Image),
-			},
-		})
-	}
+	content = append(content, images...)
 
 	// 构建请求
 	reqBody := openAIRequest{
@@ -527,10 +664,7 @@ func (a *Analyzer) callDoubao(screenshots []*models.Screenshot, start, end time.
 	}
 
 	// 豆包 API 端点
-	endpoint := "https://ark.cn-beijing.volces.com/api/v3/chat/completions"
-	if cfg.Endpoint != "" {
-		endpoint = cfg.Endpoint
-	}
+	endpoint := chatEndpoint(cfg)
 
 	req, err := http.NewRequestWithContext(context.Background(), "POST", endpoint, bytes.NewBuffer(jsonData))
 	if err != nil {
@@ -570,7 +704,7 @@ func (a *Analyzer) callDoubao(screenshots []*models.Screenshot, start, end time.
 //     "1.xxx;2.xxx;3.xxx;" 这样的编号列表；
 //   - activities 和 app_usage 依然用于前端详细信息展示。
 func (a *Analyzer) buildPrompt(start, end time.Time) string {
-	return fmt.Sprintf(`请分析 %s 至 %s 期间的工作内容。
+	prompt := fmt.Sprintf(`请分析 %s 至 %s 期间的工作内容。
 
 **重要判断规则**：
 - 如果提供的截图全部是黑屏、锁屏、空白屏幕，或者所有截图几乎完全相同（内容无明显变化），说明这段时间没有实际工作内容
@@ -609,6 +743,33 @@ func (a *Analyzer) buildPrompt(start, end time.Time) string {
 		start.Format("15:04"),
 		end.Format("15:04"),
 	)
+
+	return prompt + a.buildFewShotSection()
+}
+
+// buildFewShotSection 构建提示词中的参考示例部分
+// 示例来自用户好评或修正过的历史总结，使总结逐渐贴近用户习惯的风格
+func (a *Analyzer) buildFewShotSection() string {
+	cfg := a.configMgr.GetAI()
+	if !cfg.FewShotEnabled || cfg.FewShotExamples <= 0 {
+		return ""
+	}
+
+	examples, err := a.storage.GetFewShotExamples(cfg.FewShotExamples)
+	if err != nil {
+		logger.Warn("获取参考示例失败: %v", err)
+		return ""
+	}
+	if len(examples) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("\n\n**参考示例**（以下是用户认可的 summary 写法，请参考其措辞风格与详略程度，但内容必须来自本次截图）：\n")
+	for i, example := range examples {
+		sb.WriteString(fmt.Sprintf("示例%d: %s\n", i+1, example))
+	}
+	return sb.String()
 }
 
 // AI 响应结构
@@ -616,6 +777,13 @@ type aiResponseData struct {
 	Summary    string              `json:"summary"`
 	Activities []activityData      `json:"activities"`
 	AppUsage   map[string]int      `json:"app_usage"`
+	Captions   []captionData       `json:"captions,omitempty"`
+}
+
+// captionData 逐张截图描述（仅在启用截图描述模式时返回）
+type captionData struct {
+	Index   int    `json:"index"`
+	Caption string `json:"caption"`
 }
 
 type activityData struct {
@@ -627,21 +795,9 @@ type activityData struct {
 
 // parseResponse 解析 AI 响应
 func (a *Analyzer) parseResponse(response string, start, end time.Time) (*models.WorkSummary, error) {
-	var data aiResponseData
-
-	// 尝试提取 JSON（有些模型可能会在前后添加文本）
-	if err := json.Unmarshal([]byte(response), &data); err != nil {
-		// 尝试提取 JSON 片段
-		start := bytes.Index([]byte(response), []byte("{"))
-		end := bytes.LastIndex([]byte(response), []byte("}"))
-		if start >= 0 && end > start {
-			jsonStr := response[start : end+1]
-			if err := json.Unmarshal([]byte(jsonStr), &data); err != nil {
-				return nil, fmt.Errorf("failed to parse JSON: %w", err)
-			}
-		} else {
-			return nil, fmt.Errorf("failed to parse response: %w", err)
-		}
+	data, err := decodeResponse(response)
+	if err != nil {
+		return nil, err
 	}
 
 	// 转换为模型
@@ -651,7 +807,7 @@ func (a *Analyzer) parseResponse(response string, start, end time.Time) (*models
 		Summary:    data.Summary,
 		Activities: make([]models.Activity, len(data.Activities)),
 		AppUsage:   data.AppUsage,
-		CreatedAt:  time.Now(),
+		CreatedAt:  clock.Now(),
 	}
 
 	for i, act := range data.Activities {
@@ -666,6 +822,28 @@ func (a *Analyzer) parseResponse(response string, start, end time.Time) (*models
 	return summary, nil
 }
 
+// decodeResponse 从 AI 响应中提取 JSON 数据
+func decodeResponse(response string) (*aiResponseData, error) {
+	var data aiResponseData
+
+	// 尝试提取 JSON（有些模型可能会在前后添加文本）
+	if err := json.Unmarshal([]byte(response), &data); err != nil {
+		// 尝试提取 JSON 片段
+		start := bytes.Index([]byte(response), []byte("{"))
+		end := bytes.LastIndex([]byte(response), []byte("}"))
+		if start >= 0 && end > start {
+			jsonStr := response[start : end+1]
+			if err := json.Unmarshal([]byte(jsonStr), &data); err != nil {
+				return nil, fmt.Errorf("failed to parse JSON: %w", err)
+			}
+		} else {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+	}
+
+	return &data, nil
+}
+
 // TestConnection 测试 AI 连接并获取模型列表
 func (a *Analyzer) TestConnection(provider, apiKey, baseURL string) ([]map[string]string, error) {
 	var endpoint string
@@ -774,7 +952,7 @@ func (a *Analyzer) saveSummaryToFile(summary *models.WorkSummary) error {
 	}
 
 	// 生成文件名：summary_20250114_093045.md
-	filename := fmt.Sprintf("summary_%s.md", time.Now().Format("20060102_150405"))
+	filename := fmt.Sprintf("summary_%s.md", clock.Now().Format("20060102_150405"))
 	filePath := filepath.Join(summariesDir, filename)
 
 	// 格式化为Markdown
@@ -795,7 +973,7 @@ func (a *Analyzer) formatSummaryToMarkdown(summary *models.WorkSummary) string {
 
 	// 标题
 	sb.WriteString("# 工作分析报告\n\n")
-	sb.WriteString(fmt.Sprintf("**分析时间**: %s\n\n", time.Now().Format("2006-01-02 15:04:05")))
+	sb.WriteString(fmt.Sprintf("**分析时间**: %s\n\n", clock.Now().Format("2006-01-02 15:04:05")))
 	sb.WriteString(fmt.Sprintf("**工作时段**: %s - %s\n\n",
 		summary.StartTime.Format("15:04"),
 		summary.EndTime.Format("15:04")))
@@ -837,6 +1015,13 @@ func (a *Analyzer) formatSummaryToMarkdown(summary *models.WorkSummary) string {
 		sb.WriteString("\n")
 	}
 
+	// 截屏暂停时段
+	if gaps := a.periodGaps(summary.StartTime, summary.EndTime); len(gaps) > 0 {
+		sb.WriteString("## ☕ 暂停时段\n\n")
+		sb.WriteString(capturegap.FormatList(gaps))
+		sb.WriteString("\n\n")
+	}
+
 	// 底部信息
 	sb.WriteString("---\n\n")
 	sb.WriteString("*由 WorkTracker AI 自动生成*\n")
@@ -0,0 +1,80 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"WorkTrackerAI/pkg/clock"
+	"WorkTrackerAI/pkg/logger"
+	"WorkTrackerAI/pkg/models"
+)
+
+// buildCaptionRequestSection 构建要求模型逐张描述截图的提示词部分
+func buildCaptionRequestSection(screenshots []*models.Screenshot) string {
+	var sb strings.Builder
+	sb.WriteString("\n\n**逐张截图描述**：请在 JSON 中额外返回 captions 字段，按图片顺序为每张截图写一句不超过 30 字的描述，")
+	sb.WriteString("说明此刻正在做什么（例如使用的应用与具体内容），格式为：\n")
+	sb.WriteString(`"captions": [{"index": 1, "caption": "在 VS Code 中修改 scheduler.go"}]`)
+	sb.WriteString("\n各图片的截取时间如下：\n")
+	for i, ss := range screenshots {
+		sb.WriteString(fmt.Sprintf("图片%d: %s\n", i+1, ss.Timestamp.Format("15:04:05")))
+	}
+	return sb.String()
+}
+
+// buildCaptionContextSection 构建使用已有截图描述代替图片的提示词部分
+func buildCaptionContextSection(captions []*models.ScreenshotCaption) string {
+	var sb strings.Builder
+	sb.WriteString("\n\n**截图描述**：本次不提供图片，以下是该时间段内各时刻截图的文字描述，请据此进行分析：\n")
+	for _, c := range captions {
+		sb.WriteString(fmt.Sprintf("%s %s\n", c.Timestamp.Format("15:04:05"), c.Caption))
+	}
+	return sb.String()
+}
+
+// reusableCaptions 返回可复用的截图描述
+// 当时间段内已有的描述数量不少于本次将发送的图片数量时，直接使用描述代替图片，否则返回 nil
+func (a *Analyzer) reusableCaptions(start, end time.Time, imageCount int) []*models.ScreenshotCaption {
+	captions, err := a.storage.GetCaptions(start, end)
+	if err != nil {
+		logger.Warn("获取截图描述失败: %v", err)
+		return nil
+	}
+	if len(captions) == 0 || len(captions) < imageCount {
+		return nil
+	}
+	return captions
+}
+
+// saveCaptions 从 AI 响应中提取逐张截图描述并保存
+// sentIDs 为实际发送的截图 ID，顺序与图片顺序一致
+func (a *Analyzer) saveCaptions(response string, sentIDs []int64, run *models.AnalysisRun) {
+	data, err := decodeResponse(response)
+	if err != nil || len(data.Captions) == 0 {
+		logger.Warn("AI 响应中没有截图描述")
+		return
+	}
+
+	now := clock.Now()
+	captions := make([]*models.ScreenshotCaption, 0, len(data.Captions))
+	for _, c := range data.Captions {
+		if c.Index < 1 || c.Index > len(sentIDs) || strings.TrimSpace(c.Caption) == "" {
+			continue
+		}
+		captions = append(captions, &models.ScreenshotCaption{
+			ScreenshotID:  sentIDs[c.Index-1],
+			Caption:       strings.TrimSpace(c.Caption),
+			Provider:      run.Provider,
+			Model:         run.Model,
+			AnalysisRunID: run.ID,
+			CreatedAt:     now,
+		})
+	}
+
+	if err := a.storage.SaveScreenshotCaptions(captions); err != nil {
+		logger.Error("保存截图描述失败: %v", err)
+		return
+	}
+	logger.Info("已保存截图描述: %d 条", len(captions))
+}
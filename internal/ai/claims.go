@@ -0,0 +1,101 @@
+package ai
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"WorkTrackerAI/pkg/logger"
+	"WorkTrackerAI/pkg/models"
+)
+
+var (
+	// ErrAnalysisRunning 时间段（或与之重叠的时间段）正在分析中
+	ErrAnalysisRunning = errors.New("该时间段正在分析中")
+	// ErrAlreadyAnalyzed 时间段已存在工作总结
+	ErrAlreadyAnalyzed = errors.New("该时间段已存在工作总结")
+)
+
+// segmentClaim 一个正在分析的时间段
+type segmentClaim struct {
+	start time.Time
+	end   time.Time
+}
+
+// segmentClaims 正在分析的时间段集合，保证同一时间段同时只有一个分析在进行
+type segmentClaims struct {
+	mu     sync.Mutex
+	nextID int64
+	active map[int64]segmentClaim
+}
+
+func newSegmentClaims() *segmentClaims {
+	return &segmentClaims{active: make(map[int64]segmentClaim)}
+}
+
+// claim 占用时间段 [start, end)，与已占用时间段重叠时返回 ErrAnalysisRunning
+func (c *segmentClaims) claim(start, end time.Time) (func(), error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, other := range c.active {
+		if start.Before(other.end) && other.start.Before(end) {
+			return nil, ErrAnalysisRunning
+		}
+	}
+
+	c.nextID++
+	id := c.nextID
+	c.active[id] = segmentClaim{start: start, end: end}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			c.mu.Lock()
+			delete(c.active, id)
+			c.mu.Unlock()
+		})
+	}, nil
+}
+
+// ClaimSegment 占用时间段，调用方完成后必须调用返回的 release
+// 与正在分析的时间段重叠时返回 ErrAnalysisRunning
+func (a *Analyzer) ClaimSegment(start, end time.Time) (release func(), err error) {
+	return a.claims.claim(start, end)
+}
+
+// AnalyzeSegment 在占用时间段的前提下分析，保证每个时间段只分析一次
+// 时间段正在分析时返回 ErrAnalysisRunning，已有总结时返回 ErrAlreadyAnalyzed。
+// 是否已有总结在占用之后检查，避免检查与保存之间被其他分析插入。
+// 事件触发分析已覆盖时间段前半部分时，只分析尚未覆盖的剩余部分。
+func (a *Analyzer) AnalyzeSegment(start, end time.Time) (*models.WorkSummary, error) {
+	release, err := a.ClaimSegment(start, end)
+	if err != nil {
+		logger.Info("时间段 %s - %s 正在分析中，跳过", start.Format("2006-01-02 15:04"), end.Format("15:04"))
+		return nil, err
+	}
+	defer release()
+
+	covered, err := a.storage.SummaryCoveredUntil(start, end)
+	if err != nil {
+		return nil, err
+	}
+	if covered.IsZero() {
+		return a.AnalyzePeriod(start, end)
+	}
+	if !covered.Before(end) {
+		return nil, ErrAlreadyAnalyzed
+	}
+
+	// 剩余部分没有截图时视为已分析完成
+	count, err := a.storage.CountScreenshots(covered, end)
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, ErrAlreadyAnalyzed
+	}
+
+	logger.Info("时间段 %s - %s 已分析至 %s，继续分析剩余部分", start.Format("2006-01-02 15:04"), end.Format("15:04"), covered.Format("15:04"))
+	return a.AnalyzePeriod(covered, end)
+}
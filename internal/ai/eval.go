@@ -0,0 +1,182 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"WorkTrackerAI/pkg/clock"
+	"WorkTrackerAI/pkg/logger"
+	"WorkTrackerAI/pkg/models"
+)
+
+// 时长合理性判断允许的误差（活动总时长最多超出时间段 10%）
+const durationTolerance = 1.1
+
+// CreateEvaluation 校验参数并创建评测任务记录（不执行）
+func (a *Analyzer) CreateEvaluation(name string, periods []models.EvalPeriod, candidates []models.EvalCandidate) (*models.EvalRun, error) {
+	if len(periods) == 0 {
+		return nil, fmt.Errorf("至少需要一个评测时间段")
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("至少需要一个候选组合")
+	}
+	for _, p := range periods {
+		if !p.End.After(p.Start) {
+			return nil, fmt.Errorf("无效的时间段: %s - %s", p.Start.Format("2006-01-02 15:04"), p.End.Format("2006-01-02 15:04"))
+		}
+	}
+
+	// 补全候选的默认值，保证结果可读
+	base := a.configMgr.GetAI()
+	for i := range candidates {
+		if candidates[i].Provider == "" {
+			candidates[i].Provider = base.Provider
+		}
+		if candidates[i].Model == "" {
+			candidates[i].Model = base.Model
+		}
+		if candidates[i].Label == "" {
+			candidates[i].Label = candidates[i].Provider + "/" + candidates[i].Model
+		}
+	}
+
+	if name == "" {
+		name = fmt.Sprintf("eval_%s", clock.Now().Format("20060102_150405"))
+	}
+
+	run := &models.EvalRun{
+		Name:       name,
+		Periods:    periods,
+		Candidates: candidates,
+		Status:     models.EvalStatusRunning,
+		CreatedAt:  clock.Now(),
+	}
+	if err := a.storage.CreateEvalRun(run); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// RunEvaluation 在每个时间段上依次运行所有候选组合，并保存评测结果
+// 同一时间段内所有候选使用相同的截图采样，结果不会写入工作总结
+func (a *Analyzer) RunEvaluation(run *models.EvalRun) error {
+	logger.Info("开始评测 %d (%s): %d 个时间段, %d 个候选", run.ID, run.Name, len(run.Periods), len(run.Candidates))
+
+	base := a.configMgr.GetAI()
+	for _, period := range run.Periods {
+		screenshots, err := a.storage.GetScreenshots(period.Start, period.End)
+		if err != nil {
+			a.finishEvaluation(run, models.EvalStatusFailed, err.Error())
+			return fmt.Errorf("failed to get screenshots: %w", err)
+		}
+		sampled := a.sampleScreenshots(uniqueFrames(screenshots), base.MaxImages)
+		images := a.prepareImages(sampled)
+
+		for _, cand := range run.Candidates {
+			res := a.evaluateCandidate(base, cand, period, images)
+			res.EvalRunID = run.ID
+			if err := a.storage.SaveEvalResult(res); err != nil {
+				logger.Error("保存评测结果失败: %v", err)
+			}
+			run.Results = append(run.Results, res)
+		}
+	}
+
+	a.finishEvaluation(run, models.EvalStatusDone, "")
+	logger.Info("评测 %d 完成，共 %d 条结果", run.ID, len(run.Results))
+	return nil
+}
+
+// finishEvaluation 更新评测任务状态
+func (a *Analyzer) finishEvaluation(run *models.EvalRun, status, errMsg string) {
+	finishedAt := clock.Now()
+	run.Status = status
+	run.Error = errMsg
+	run.FinishedAt = &finishedAt
+	if err := a.storage.FinishEvalRun(run.ID, status, errMsg, finishedAt); err != nil {
+		logger.Error("更新评测状态失败: %v", err)
+	}
+}
+
+// evaluateCandidate 使用指定候选组合分析一个时间段，并计算自动指标
+func (a *Analyzer) evaluateCandidate(base models.AIConfig, cand models.EvalCandidate, period models.EvalPeriod, images []uploadImage) *models.EvalResult {
+	cfg := base
+	cfg.Provider = cand.Provider
+	cfg.Model = cand.Model
+	if cand.Endpoint != "" {
+		cfg.Endpoint = cand.Endpoint
+	}
+	if cand.APIKey != "" {
+		cfg.APIKey = cand.APIKey
+	}
+
+	prompt := a.buildPrompt(period.Start, period.End)
+	if cand.Prompt != "" {
+		prompt = renderPromptTemplate(cand.Prompt, period.Start, period.End)
+	}
+
+	res := &models.EvalResult{
+		PeriodStart: period.Start,
+		PeriodEnd:   period.End,
+		Candidate:   cand.Label,
+		Provider:    cfg.Provider,
+		Model:       cfg.Model,
+		Prompt:      prompt,
+		CreatedAt:   clock.Now(),
+	}
+
+	if len(images) == 0 {
+		res.Error = "时间段内没有截图数据"
+		return res
+	}
+
+	callStart := time.Now()
+	response, sentIDs, err := a.callLLM(cfg, prompt, images)
+	res.LatencyMs = time.Since(callStart).Milliseconds()
+	res.ScreenshotIDs = sentIDs
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	res.RawResponse = response
+
+	summary, err := a.parseResponse(response, period.Start, period.End)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+
+	res.JSONValid = true
+	res.Summary = summary.Summary
+	res.DurationPlausible, res.TotalMinutes = durationPlausible(summary, period.Start, period.End)
+	return res
+}
+
+// renderPromptTemplate 替换提示词模板中的 {start}/{end} 占位符
+func renderPromptTemplate(tmpl string, start, end time.Time) string {
+	return strings.NewReplacer(
+		"{start}", start.Format("15:04"),
+		"{end}", end.Format("15:04"),
+	).Replace(tmpl)
+}
+
+// durationPlausible 判断活动总时长是否合理
+// 活动总时长必须为正且不超过时间段长度（允许少量误差）；
+// 没有活动时仅当总结为空内容时才视为合理。
+func durationPlausible(summary *models.WorkSummary, start, end time.Time) (bool, int) {
+	total := 0
+	for _, act := range summary.Activities {
+		if act.DurationMinutes < 0 {
+			return false, total
+		}
+		total += act.DurationMinutes
+	}
+
+	if total == 0 {
+		return len(summary.Activities) == 0, total
+	}
+
+	limit := end.Sub(start).Minutes() * durationTolerance
+	return float64(total) <= limit, total
+}
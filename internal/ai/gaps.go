@@ -0,0 +1,30 @@
+package ai
+
+import (
+	"time"
+
+	"WorkTrackerAI/pkg/capturegap"
+	"WorkTrackerAI/pkg/logger"
+	"WorkTrackerAI/pkg/models"
+)
+
+// periodGaps 获取时间段内的截屏暂停记录（已截断到时间段内）
+func (a *Analyzer) periodGaps(start, end time.Time) []*models.CaptureGap {
+	gaps, err := a.storage.GetCaptureGaps(start, end)
+	if err != nil {
+		logger.Warn("获取截屏暂停记录失败: %v", err)
+		return nil
+	}
+	return capturegap.Clip(gaps, start, end)
+}
+
+// buildGapSection 构建截屏暂停时段的提示词部分，避免模型把离开时间算作工作时长或推测暂停期间的内容
+func (a *Analyzer) buildGapSection(start, end time.Time) string {
+	gaps := a.periodGaps(start, end)
+	if len(gaps) == 0 {
+		return ""
+	}
+
+	return "\n\n**截屏暂停时段**：以下时段没有截图，不要推测这些时段的内容；其中离开时段与私密模式（用户主动排除的时段）不属于工作时间，估算活动时长时请扣除：\n" +
+		capturegap.FormatList(gaps) + "\n"
+}
@@ -0,0 +1,115 @@
+package ai
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+
+	"WorkTrackerAI/pkg/activewindow"
+	"WorkTrackerAI/pkg/imageformat"
+	"WorkTrackerAI/pkg/logger"
+	"WorkTrackerAI/pkg/models"
+	"WorkTrackerAI/pkg/privacy"
+	"WorkTrackerAI/pkg/redact"
+)
+
+// uploadRedactor 在截图发送给 AI 前遮挡固定区域与匹配遮挡规则的窗口，本地文件保持不变
+type uploadRedactor struct {
+	cfg     models.PrivacyConfig
+	matcher *privacy.Matcher
+	windows map[int64]*models.ScreenshotWindow
+	quality int
+}
+
+// newUploadRedactor 按当前隐私配置创建遮挡器，并预先读取截图的前台窗口
+func (a *Analyzer) newUploadRedactor(screenshots []*models.Screenshot) *uploadRedactor {
+	cfg := a.configMgr.GetPrivacy()
+	matcher, err := privacy.Compile(cfg.Rules)
+	if err != nil {
+		logger.Warn("部分隐私规则无效，已忽略: %v", err)
+	}
+
+	ids := make([]int64, 0, len(screenshots))
+	for _, ss := range screenshots {
+		ids = append(ids, ss.ID)
+		if ss.SourceID != 0 {
+			ids = append(ids, ss.SourceID)
+		}
+	}
+	windows, err := a.storage.GetScreenshotWindowsByIDs(ids)
+	if err != nil {
+		logger.Warn("获取前台窗口记录失败: %v", err)
+	}
+
+	return &uploadRedactor{
+		cfg:     cfg,
+		matcher: matcher,
+		windows: windows,
+		quality: a.configMgr.GetCapture().Quality,
+	}
+}
+
+// window 截图画面对应的前台窗口（无变化心跳使用原截图的窗口，因为文件来自原截图）
+func (r *uploadRedactor) window(ss *models.Screenshot) *models.ScreenshotWindow {
+	if ss.Unchanged && ss.SourceID != 0 {
+		if w, ok := r.windows[ss.SourceID]; ok {
+			return w
+		}
+	}
+	return r.windows[ss.ID]
+}
+
+// regions 返回截图需要遮挡的区域；窗口需要遮挡但位置未知时返回 false，此时不应发送该截图
+func (r *uploadRedactor) regions(ss *models.Screenshot) ([]models.RelativeRect, bool) {
+	regions := redact.Regions(r.cfg, ss.ScreenIndex)
+	if w := r.window(ss); w != nil && windowRedacted(r.matcher, w) {
+		if redact.Empty(w.Region) {
+			return nil, false
+		}
+		regions = append(regions, w.Region)
+	}
+	return regions, true
+}
+
+// apply 解码截图、遮挡区域后按原格式重新编码，返回新的图片数据与 MIME 类型
+func (r *uploadRedactor) apply(data []byte, ss *models.Screenshot, regions []models.RelativeRect) ([]byte, string, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode screenshot: %w", err)
+	}
+
+	rgba := redact.ToRGBA(img)
+	redact.Apply(rgba, regions, r.cfg.RedactionStyle)
+
+	encoder, ok := imageformat.Lookup(ss.Format)
+	if !ok {
+		if encoder, ok = imageformat.Lookup(imageformat.FromPath(ss.FilePath)); !ok {
+			encoder = imageformat.Default()
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := encoder.Encode(&buf, rgba, r.quality); err != nil {
+		return nil, "", fmt.Errorf("failed to encode %s: %w", encoder.Name(), err)
+	}
+	return buf.Bytes(), encoder.MIMEType(), nil
+}
+
+// windowRedacted 窗口是否匹配遮挡规则（其画面与标题都不应发送给 AI）
+func windowRedacted(matcher *privacy.Matcher, w *models.ScreenshotWindow) bool {
+	rule := matcher.Match(&activewindow.Info{Title: w.Title, ProcessName: w.ProcessName})
+	return rule != nil && rule.Action == models.PrivacyRedact
+}
+
+// buildRedactionSection 启用遮挡时提示模型忽略被遮挡的区域
+func (a *Analyzer) buildRedactionSection() string {
+	cfg := a.configMgr.GetPrivacy()
+	enabled := len(cfg.Redactions) > 0
+	for _, rule := range cfg.Rules {
+		enabled = enabled || rule.Action == models.PrivacyRedact
+	}
+	if !enabled {
+		return ""
+	}
+	return "\n\n**隐私遮挡**：截图中的灰色色块或模糊区域是出于隐私遮挡的内容，请忽略这些区域，不要猜测其中的内容。\n"
+}
@@ -0,0 +1,128 @@
+package ai
+
+import (
+	"fmt"
+	"time"
+
+	"WorkTrackerAI/pkg/clock"
+	"WorkTrackerAI/pkg/logger"
+	"WorkTrackerAI/pkg/models"
+)
+
+// 重放模式
+const (
+	ReplayModeParse  = "parse"  // 仅使用记录中的原始响应重新解析
+	ReplayModeResend = "resend" // 使用记录中的提示词和截图重新发送请求
+)
+
+// ReplayResult 重放结果
+type ReplayResult struct {
+	Mode        string              `json:"mode"`
+	Original    *models.AnalysisRun `json:"original"`
+	Run         *models.AnalysisRun `json:"run,omitempty"` // resend 模式下新记录
+	RawResponse string              `json:"raw_response"`
+	ParseStatus string              `json:"parse_status"`
+	Error       string              `json:"error,omitempty"`
+	Summary     *models.WorkSummary `json:"summary,omitempty"`
+}
+
+// newAnalysisRun 创建一条分析记录（尚未保存）
+func newAnalysisRun(start, end time.Time, cfg models.AIConfig, prompt string) *models.AnalysisRun {
+	return &models.AnalysisRun{
+		StartTime:   start,
+		EndTime:     end,
+		Provider:    cfg.Provider,
+		Model:       cfg.Model,
+		MaxTokens:   cfg.MaxTokens,
+		Temperature: cfg.Temperature,
+		Prompt:      prompt,
+		CreatedAt:   clock.Now(),
+	}
+}
+
+// saveRun 保存分析记录，失败只记录日志，不影响分析流程
+func (a *Analyzer) saveRun(run *models.AnalysisRun) {
+	if err := a.storage.SaveAnalysisRun(run); err != nil {
+		logger.Error("保存分析记录失败: %v", err)
+	}
+}
+
+// ReplayRun 重放一次历史分析，用于调试提示词与解析逻辑
+// 重放不会修改已有的工作总结
+//   - parse：对记录中的原始响应重新执行解析；
+//   - resend：使用相同的提供商、模型、提示词和截图重新发送请求，并保存为新的分析记录。
+func (a *Analyzer) ReplayRun(id int64, mode string) (*ReplayResult, error) {
+	original, err := a.storage.GetAnalysisRun(id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReplayResult{
+		Mode:     mode,
+		Original: original,
+	}
+
+	switch mode {
+	case ReplayModeParse:
+		if original.ParseStatus == models.ParseStatusCallFailed {
+			return nil, fmt.Errorf("analysis run %d has no response to parse", id)
+		}
+		result.RawResponse = original.RawResponse
+
+	case ReplayModeResend:
+		screenshots, err := a.storage.GetScreenshotsByIDs(original.ScreenshotIDs)
+		if err != nil {
+			return nil, err
+		}
+		if len(screenshots) != len(original.ScreenshotIDs) {
+			logger.Warn("重放记录 %d: 原始 %d 张截图中仅找到 %d 张", id, len(original.ScreenshotIDs), len(screenshots))
+		}
+
+		// 使用当前的密钥与端点，但保持记录中的提供商、模型与生成参数
+		cfg := a.configMgr.GetAI()
+		cfg.Provider = original.Provider
+		cfg.Model = original.Model
+		cfg.MaxTokens = original.MaxTokens
+		cfg.Temperature = original.Temperature
+
+		run := newAnalysisRun(original.StartTime, original.EndTime, cfg, original.Prompt)
+		run.ReplayOf = original.ID
+		result.Run = run
+
+		callStart := time.Now()
+		response, sentIDs, err := a.callLLM(cfg, original.Prompt, a.prepareImages(screenshots))
+		run.LatencyMs = time.Since(callStart).Milliseconds()
+		run.ScreenshotIDs = sentIDs
+		if err != nil {
+			run.ParseStatus = models.ParseStatusCallFailed
+			run.Error = err.Error()
+			a.saveRun(run)
+			result.ParseStatus = run.ParseStatus
+			result.Error = run.Error
+			return result, nil
+		}
+		run.RawResponse = response
+		result.RawResponse = response
+
+	default:
+		return nil, fmt.Errorf("unknown replay mode: %s", mode)
+	}
+
+	summary, err := a.parseResponse(result.RawResponse, original.StartTime, original.EndTime)
+	if err != nil {
+		result.ParseStatus = models.ParseStatusParseFailed
+		result.Error = err.Error()
+	} else {
+		result.ParseStatus = models.ParseStatusOK
+		result.Summary = summary
+	}
+
+	if result.Run != nil {
+		result.Run.ParseStatus = result.ParseStatus
+		result.Run.Error = result.Error
+		a.saveRun(result.Run)
+	}
+
+	logger.Info("重放分析记录 %d (模式: %s): %s", id, mode, result.ParseStatus)
+	return result, nil
+}
@@ -0,0 +1,38 @@
+package ai
+
+import (
+	"time"
+
+	"WorkTrackerAI/pkg/appusage"
+	"WorkTrackerAI/pkg/logger"
+	"WorkTrackerAI/pkg/models"
+)
+
+// measureAppUsage 根据前台窗口采样计算时间段内的实测使用时长，没有窗口记录时返回 nil
+func (a *Analyzer) measureAppUsage(start, end time.Time) *models.AppUsageReport {
+	windows, err := a.storage.GetScreenshotWindows(start, end)
+	if err != nil {
+		logger.Warn("获取前台窗口记录失败: %v", err)
+		return nil
+	}
+	if len(windows) == 0 {
+		return nil
+	}
+
+	// 离开、隐私规则与私密模式时段不计入任何应用
+	report := appusage.Compute(windows, a.periodGaps(start, end), start, end, appusage.MaxGap(a.configMgr.GetCapture()))
+	if len(report.Apps) == 0 {
+		return nil
+	}
+	return report
+}
+
+// saveAppUsage 按总结保存实测使用时长
+func (a *Analyzer) saveAppUsage(summaryID int64, report *models.AppUsageReport) {
+	if report == nil {
+		return
+	}
+	if err := a.storage.SaveSegmentAppUsage(summaryID, appusage.Rows(report, summaryID)); err != nil {
+		logger.Warn("保存应用使用时长失败: %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package ai
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"WorkTrackerAI/pkg/logger"
+	"WorkTrackerAI/pkg/models"
+	"WorkTrackerAI/pkg/privacy"
+)
+
+// 提示词中最多列出的进程数量
+const maxWindowProcesses = 10
+
+// 窗口标题在提示词中的最大长度（字符）
+const maxWindowTitleLen = 80
+
+// buildWindowSection 构建前台窗口记录的提示词部分
+// 包含时间段内各进程的出现比例，以及本次发送的每张图片截取时的前台窗口
+func (a *Analyzer) buildWindowSection(start, end time.Time, images []*models.Screenshot) string {
+	windows, err := a.storage.GetScreenshotWindows(start, end)
+	if err != nil {
+		logger.Warn("获取前台窗口记录失败: %v", err)
+		return ""
+	}
+	if len(windows) == 0 {
+		return ""
+	}
+
+	// 匹配遮挡规则的窗口只保留进程名，不发送标题
+	matcher, _ := privacy.Compile(a.configMgr.GetPrivacy().Rules)
+	for _, w := range windows {
+		if windowRedacted(matcher, w) {
+			w.Title = ""
+		}
+	}
+
+	type processStat struct {
+		name   string
+		count  int
+		titles map[string]int
+	}
+	stats := make(map[string]*processStat)
+	byScreenshot := make(map[int64]*models.ScreenshotWindow, len(windows))
+	for _, w := range windows {
+		byScreenshot[w.ScreenshotID] = w
+		name := processLabel(w)
+		st, ok := stats[name]
+		if !ok {
+			st = &processStat{name: name, titles: make(map[string]int)}
+			stats[name] = st
+		}
+		st.count++
+		if w.Title != "" {
+			st.titles[w.Title]++
+		}
+	}
+
+	ranked := make([]*processStat, 0, len(stats))
+	for _, st := range stats {
+		ranked = append(ranked, st)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].count != ranked[j].count {
+			return ranked[i].count > ranked[j].count
+		}
+		return ranked[i].name < ranked[j].name
+	})
+	if len(ranked) > maxWindowProcesses {
+		ranked = ranked[:maxWindowProcesses]
+	}
+
+	var sb strings.Builder
+	sb.WriteString("\n\n**前台窗口记录**：以下是截图时系统记录的前台窗口（进程名与窗口标题），比从画面中识别更准确，")
+	sb.WriteString("判断应用名称时请以此为准，并在 apps 中使用易读的应用名（如 chrome.exe 写作 Chrome）。")
+	sb.WriteString("app_usage 将由系统按窗口记录实测计算，可以留空。\n")
+	sb.WriteString("各进程在截图中的出现比例：\n")
+	for _, st := range ranked {
+		line := fmt.Sprintf("- %s: %d%%", st.name, st.count*100/len(windows))
+		if title := mostFrequent(st.titles); title != "" {
+			line += "，常见标题：" + truncateTitle(title)
+		}
+		sb.WriteString(line + "\n")
+	}
+
+	var imageLines []string
+	for i, ss := range images {
+		if w, ok := byScreenshot[ss.ID]; ok {
+			line := fmt.Sprintf("图片%d (%s): %s", i+1, ss.Timestamp.Format("15:04:05"), processLabel(w))
+			if w.Title != "" {
+				line += " — " + truncateTitle(w.Title)
+			}
+			imageLines = append(imageLines, line)
+		}
+	}
+	if len(imageLines) > 0 {
+		sb.WriteString("各图片截取时的前台窗口：\n")
+		sb.WriteString(strings.Join(imageLines, "\n"))
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// processLabel 窗口所属进程的显示名称
+func processLabel(w *models.ScreenshotWindow) string {
+	if w.ProcessName != "" {
+		return w.ProcessName
+	}
+	return "未知进程"
+}
+
+// mostFrequent 返回出现次数最多的标题
+func mostFrequent(titles map[string]int) string {
+	best, bestCount := "", 0
+	for title, count := range titles {
+		if count > bestCount || (count == bestCount && title < best) {
+			best, bestCount = title, count
+		}
+	}
+	return best
+}
+
+// truncateTitle 截断过长的窗口标题
+func truncateTitle(title string) string {
+	runes := []rune(title)
+	if len(runes) <= maxWindowTitleLen {
+		return title
+	}
+	return string(runes[:maxWindowTitleLen]) + "…"
+}
@@ -5,18 +5,23 @@ import (
 	"context"
 	"fmt"
 	"image"
-	"image/jpeg"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
 	"WorkTrackerAI/internal/config"
 	"WorkTrackerAI/internal/storage"
+	"WorkTrackerAI/pkg/activewindow"
+	"WorkTrackerAI/pkg/clock"
+	"WorkTrackerAI/pkg/imagediff"
+	"WorkTrackerAI/pkg/imageformat"
 	"WorkTrackerAI/pkg/logger"
 	"WorkTrackerAI/pkg/models"
+	"WorkTrackerAI/pkg/privacy"
 	"WorkTrackerAI/pkg/screenstate"
-	"WorkTrackerAI/pkg/utils"
+	"WorkTrackerAI/pkg/workday"
 
 	"github.com/kbinani/screenshot"
 	"github.com/nfnt/resize"
@@ -32,13 +37,76 @@ type Engine struct {
 	running   bool
 	mu        sync.RWMutex
 	lastCapture time.Time
+
+	frames map[int]storedFrame // 每个屏幕最近一次写入文件的截图，用于跳过无变化画面
+
+	interval     time.Duration // 当前实际截屏间隔（自适应时在基础间隔与上限之间变化）
+	roundChanged bool          // 本轮截屏中是否有屏幕画面发生变化
+
+	windows      ActiveWindowProvider // 前台窗口信息来源，nil 表示不记录
+	windowWarned bool                 // 获取前台窗口失败是否已提示过
+
+	idleGap    *models.CaptureGap // 进行中的键鼠空闲时段，nil 表示未空闲
+	idleWarned bool               // 获取键鼠空闲时长失败是否已提示过
+
+	privacy    *privacy.Matcher   // 已编译的隐私规则，规则变化后置空并在下次截屏前重新编译
+	privacyGap *models.CaptureGap // 进行中的隐私规则暂停时段
+
+	private *models.CaptureGap // 进行中的私密模式时段，EndTime 为计划结束时间
+}
+
+// shotContext 本轮截屏共用的前台窗口与隐私处理结果
+type shotContext struct {
+	window   *activewindow.Info // 截屏时的前台窗口，nil 表示未知或不记录
+	blackout image.Rectangle    // 需要涂黑的区域（虚拟桌面坐标），为空表示不处理
+}
+
+// storedFrame 已写入文件的截图及其画面指纹
+type storedFrame struct {
+	shot *models.Screenshot
+	fp   imagediff.Fingerprint
 }
 
 // NewEngine 创建截屏引擎
 func NewEngine(configMgr *config.Manager, storageMgr *storage.Manager) *Engine {
-	return &Engine{
+	e := &Engine{
 		configMgr: configMgr,
 		storage:   storageMgr,
+		frames:    make(map[int]storedFrame),
+		windows:   systemWindowProvider{},
+	}
+	e.restorePrivate()
+	configMgr.Subscribe(e.onConfigChange)
+	return e
+}
+
+// onConfigChange 配置变更时立即应用截屏间隔与启用状态
+func (e *Engine) onConfigChange(change *config.Change) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if change.Changed("privacy.") {
+		e.privacy = nil
+	}
+
+	if !e.running {
+		return
+	}
+
+	if change.Changed("capture.enabled") && !change.New.Capture.Enabled {
+		e.cancel()
+		e.ticker.Stop()
+		e.running = false
+		e.closeGaps(clock.Now())
+		logger.Info("截屏功能已在配置中关闭，截屏引擎已停止")
+		return
+	}
+
+	// 基础间隔或自适应设置变化时回到基础间隔重新计算
+	if change.Changed("capture.interval", "capture.adaptive_interval", "capture.max_interval", "capture.interval_growth") && change.New.Capture.Interval > 0 {
+		e.interval = time.Duration(change.New.Capture.Interval) * time.Second
+		e.ticker.Reset(e.interval)
+		logger.Info("截屏间隔已更新为 %d秒", change.New.Capture.Interval)
 	}
 }
 
@@ -58,8 +126,16 @@ func (e *Engine) Start() error {
 		return fmt.Errorf("capture is disabled in config")
 	}
 
+	// 上次异常退出时遗留的暂停时段保留到最后一次检测的时间
+	for _, kind := range []string{models.GapIdle, models.GapPrivacy} {
+		if err := e.storage.CloseOpenCaptureGaps(kind); err != nil {
+			logger.Warn("结束遗留的暂停时段失败: %v", err)
+		}
+	}
+
 	e.ctx, e.cancel = context.WithCancel(context.Background())
-	e.ticker = time.NewTicker(time.Duration(cfg.Interval) * time.Second)
+	e.interval = time.Duration(cfg.Interval) * time.Second
+	e.ticker = time.NewTicker(e.interval)
 	e.running = true
 
 	go e.captureLoop()
@@ -80,6 +156,7 @@ func (e *Engine) Stop() error {
 	e.cancel()
 	e.ticker.Stop()
 	e.running = false
+	e.closeGaps(clock.Now())
 
 	logger.Info("截屏引擎已停止")
 	return nil
@@ -99,6 +176,16 @@ func (e *Engine) GetLastCapture() time.Time {
 	return e.lastCapture
 }
 
+// GetEffectiveInterval 获取当前实际截屏间隔（未运行时为 0）
+func (e *Engine) GetEffectiveInterval() time.Duration {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if !e.running {
+		return 0
+	}
+	return e.interval
+}
+
 // captureLoop 截屏循环
 func (e *Engine) captureLoop() {
 	logger.Info("截屏循环已启动")
@@ -125,24 +212,25 @@ func (e *Engine) shouldCapture() bool {
 		return true
 	}
 
-	// 检查星期几
-	now := time.Now()
-	if !utils.IsDayInList(now.Weekday(), schedule.WorkDays) {
-		return false
-	}
-
-	// 检查时间范围
-	inRange, err := utils.TimeInRange(schedule.StartTime, schedule.EndTime)
-	if err != nil {
-		logger.Error("时间范围检查错误: %v", err)
-		return false
-	}
-
-	return inRange
+	// 检查是否为工作日且在某个工作时段内（午休等时段之间不截屏）
+	return workday.InWorkTime(schedule, clock.Now())
 }
 
 // captureAll 截取所有配置的屏幕
 func (e *Engine) captureAll() error {
+	cfg := e.configMgr.GetCapture()
+
+	// 私密模式期间不截屏，也不检测离开与隐私规则
+	if e.privatePaused(clock.Now()) {
+		return nil
+	}
+
+	// 键鼠长时间无操作（人已离开）时不截屏，只记录空闲时段
+	if e.idlePaused(cfg) {
+		e.endPrivacySkip(clock.Now())
+		return nil
+	}
+
 	// 检测屏幕状态：如果屏幕被锁定或屏保运行中，跳过截屏
 	active, screensaverRunning, screenLocked := screenstate.GetScreenStateInfo()
 	
@@ -157,42 +245,78 @@ func (e *Engine) captureAll() error {
 		} else {
 			logger.Info("⏸️  屏幕未激活，跳过本次截屏")
 		}
+		e.endPrivacySkip(clock.Now())
 		return nil
 	}
 	
 	logger.Debug("✅ 屏幕状态正常，开始截屏")
 
-	cfg := e.configMgr.GetCapture()
+	// 前台窗口匹配隐私规则时跳过本轮截屏，或涂黑该窗口
+	shot, skip := e.applyPrivacy(e.activeWindow())
+	if skip != nil {
+		return nil
+	}
+
+	e.mu.Lock()
+	e.roundChanged = false
+	e.mu.Unlock()
 
 	// 如果启用多屏幕拼接，则拼接所有屏幕
 	if cfg.MergeScreens {
 		n := screenshot.NumActiveDisplays()
 		if n > 1 {
-			return e.captureMergedScreens()
+			return e.captureMergedScreens(shot)
 		}
 		// 只有一个屏幕时，正常截取
-		if err := e.captureScreen(0); err != nil {
+		if err := e.captureScreen(0, shot); err != nil {
 			return fmt.Errorf("failed to capture screen 0: %w", err)
 		}
 	} else {
 		// 不拼接时，按配置截取选定的屏幕
 		for _, screenIndex := range cfg.SelectedScreens {
-			if err := e.captureScreen(screenIndex); err != nil {
+			if err := e.captureScreen(screenIndex, shot); err != nil {
 				return fmt.Errorf("failed to capture screen %d: %w", screenIndex, err)
 			}
 		}
 	}
 
 	e.mu.Lock()
-	e.lastCapture = time.Now()
+	e.lastCapture = clock.Now()
+	e.adjustInterval(cfg)
 	e.mu.Unlock()
 
 	logger.Debug("截屏完成")
 	return nil
 }
 
+// adjustInterval 根据本轮画面变化调整截屏间隔（调用方需持有锁）
+// 画面静止时按增长倍数逐步拉长到上限，画面变化时立即回到基础间隔
+func (e *Engine) adjustInterval(cfg models.CaptureConfig) {
+	if !e.running || cfg.Interval <= 0 {
+		return
+	}
+
+	base := time.Duration(cfg.Interval) * time.Second
+	next := base
+	if cfg.AdaptiveInterval && !e.roundChanged {
+		next = time.Duration(float64(e.interval) * cfg.IntervalGrowth)
+		if max := time.Duration(cfg.MaxInterval) * time.Second; next > max {
+			next = max
+		}
+		if next < base {
+			next = base
+		}
+	}
+
+	if next != e.interval {
+		logger.Debug("截屏间隔调整: %v -> %v", e.interval, next)
+		e.interval = next
+		e.ticker.Reset(next)
+	}
+}
+
 // captureMergedScreens 截取并拼接所有屏幕
-func (e *Engine) captureMergedScreens() error {
+func (e *Engine) captureMergedScreens(shot shotContext) error {
 	n := screenshot.NumActiveDisplays()
 	if n == 0 {
 		return fmt.Errorf("no active displays found")
@@ -258,7 +382,7 @@ func (e *Engine) captureMergedScreens() error {
 
 	// 4. 保存拼接后的图像
 	mergedBounds := image.Rect(minX, minY, maxX, maxY)
-	if err := e.saveScreenshot(merged, -1, mergedBounds); err != nil {
+	if err := e.saveScreenshot(merged, -1, mergedBounds, shot); err != nil {
 		return fmt.Errorf("failed to save merged screenshot: %w", err)
 	}
 
@@ -267,7 +391,7 @@ func (e *Engine) captureMergedScreens() error {
 }
 
 // captureScreen 截取指定屏幕
-func (e *Engine) captureScreen(screenIndex int) error {
+func (e *Engine) captureScreen(screenIndex int, shot shotContext) error {
 	// 获取屏幕数量
 	n := screenshot.NumActiveDisplays()
 	if screenIndex < 0 || screenIndex >= n {
@@ -284,13 +408,17 @@ func (e *Engine) captureScreen(screenIndex int) error {
 	}
 
 	// 保存截图
-	return e.saveScreenshot(img, screenIndex, bounds)
+	return e.saveScreenshot(img, screenIndex, bounds, shot)
 }
 
-// saveScreenshot 保存截图（支持智能压缩和缩放）
-func (e *Engine) saveScreenshot(img *image.RGBA, screenIndex int, bounds image.Rectangle) error {
+// saveScreenshot 保存截图（支持智能压缩和缩放，画面无变化时只记录心跳）
+func (e *Engine) saveScreenshot(img *image.RGBA, screenIndex int, bounds image.Rectangle, shot shotContext) error {
 	cfg := e.configMgr.GetCapture()
 	storageCfg := e.configMgr.GetStorage()
+	window := shot.window
+
+	// 涂黑匹配隐私规则的窗口，必须在缩放、比较和编码之前完成
+	blackout(img, bounds, shot.blackout)
 
 	// 1. 智能缩放（如果启用）
 	processedImg := image.Image(img)
@@ -326,11 +454,31 @@ func (e *Engine) saveScreenshot(img *image.RGBA, screenIndex int, bounds image.R
 		}
 	}
 
-	// 2. 确定文件扩展名（暂时只支持 JPEG）
-	fileExt := ".jpg"
+	// 2. 与上一张已保存的截图比较画面变化
+	now := clock.Now()
+	resolution := fmt.Sprintf("%dx%d", finalWidth, finalHeight)
+	trackFrames := cfg.SkipUnchanged || cfg.AdaptiveInterval
+	var fp imagediff.Fingerprint
+	if trackFrames {
+		fp = imagediff.Compute(processedImg)
+		prev := e.similarFrame(screenIndex, fp, resolution, cfg.UnchangedThreshold)
+		if prev == nil {
+			e.mu.Lock()
+			e.roundChanged = true
+			e.mu.Unlock()
+		}
 
-	// 3. 生成文件名
-	now := time.Now()
+		// 画面几乎相同时只记录心跳，不写入新文件
+		if cfg.SkipUnchanged && prev != nil && reusable(prev, cfg, now) {
+			return e.saveHeartbeat(prev, now, window, bounds)
+		}
+	}
+
+	// 3. 选择编码器，决定文件扩展名
+	encoder := encoderFor(cfg.ImageFormat)
+	fileExt := encoder.Extension()
+
+	// 4. 生成文件名
 	var filename string
 	if screenIndex == -1 {
 		filename = fmt.Sprintf("screenshot_merged_%s%s", now.Format("20060102_150405"), fileExt)
@@ -338,7 +486,7 @@ func (e *Engine) saveScreenshot(img *image.RGBA, screenIndex int, bounds image.R
 		filename = fmt.Sprintf("screenshot_%d_%s%s", screenIndex, now.Format("20060102_150405"), fileExt)
 	}
 
-	// 4. 确保目录存在
+	// 5. 确保目录存在
 	screenshotsDir := storageCfg.ScreenshotsDir
 	if screenshotsDir == "" {
 		screenshotsDir = filepath.Join(storageCfg.DataDir, "screenshots")
@@ -350,28 +498,25 @@ func (e *Engine) saveScreenshot(img *image.RGBA, screenIndex int, bounds image.R
 
 	filePath := filepath.Join(dateDir, filename)
 
-	// 5. JPEG 压缩编码
+	// 6. 压缩编码
 	var buf bytes.Buffer
-	encodeErr := jpeg.Encode(&buf, processedImg, &jpeg.Options{
-		Quality: cfg.Quality,
-	})
-
-	if encodeErr != nil {
-		return fmt.Errorf("failed to encode JPEG: %w", encodeErr)
+	if err := encoder.Encode(&buf, processedImg, cfg.Quality); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", encoder.Name(), err)
 	}
 
-	// 6. 写入文件
+	// 7. 写入文件
 	if err := os.WriteFile(filePath, buf.Bytes(), 0644); err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
 
-	// 7. 保存到数据库
+	// 8. 保存到数据库
 	ss := &models.Screenshot{
 		Timestamp:   now,
 		ScreenIndex: screenIndex,
 		FilePath:    filePath,
 		FileSize:    int64(buf.Len()),
-		Resolution:  fmt.Sprintf("%dx%d", finalWidth, finalHeight),
+		Resolution:  resolution,
+		Format:      encoder.Name(),
 		Analyzed:    false,
 		CreatedAt:   now,
 	}
@@ -379,11 +524,105 @@ func (e *Engine) saveScreenshot(img *image.RGBA, screenIndex int, bounds image.R
 	if err := e.storage.SaveScreenshot(ss); err != nil {
 		return fmt.Errorf("failed to save to database: %w", err)
 	}
+	e.saveWindow(ss.ID, window, bounds)
 
-	logger.Debug("截图已保存: %s (%.2f KB)", filePath, float64(buf.Len())/1024)
+	if trackFrames {
+		e.mu.Lock()
+		e.frames[screenIndex] = storedFrame{shot: ss, fp: fp}
+		e.mu.Unlock()
+	}
+
+	logger.Debug("截图已保存: %s (%s, %.2f KB)", filePath, encoder.Name(), float64(buf.Len())/1024)
 	return nil
 }
 
+// similarFrame 返回与本次画面几乎相同的上一张截图，画面有变化时返回 nil
+func (e *Engine) similarFrame(screenIndex int, fp imagediff.Fingerprint, resolution string, threshold float64) *models.Screenshot {
+	e.mu.RLock()
+	prev, ok := e.frames[screenIndex]
+	e.mu.RUnlock()
+	if !ok || prev.shot.Resolution != resolution {
+		return nil
+	}
+	if imagediff.Difference(prev.fp, fp) > threshold {
+		return nil
+	}
+	return prev.shot
+}
+
+// reusable 判断无变化画面能否复用上一张截图的文件
+func reusable(prev *models.Screenshot, cfg models.CaptureConfig, now time.Time) bool {
+	// 连续无变化过久时保存一张完整截图，避免时间段内只剩心跳记录
+	if cfg.UnchangedMaxAge > 0 && now.Sub(prev.Timestamp) >= time.Duration(cfg.UnchangedMaxAge)*time.Second {
+		return false
+	}
+
+	// 原文件已被清理时重新保存
+	_, err := os.Stat(prev.FilePath)
+	return err == nil
+}
+
+// saveHeartbeat 保存无变化心跳记录，文件路径指向上一张截图
+func (e *Engine) saveHeartbeat(prev *models.Screenshot, now time.Time, window *activewindow.Info, bounds image.Rectangle) error {
+	ss := &models.Screenshot{
+		Timestamp:   now,
+		ScreenIndex: prev.ScreenIndex,
+		FilePath:    prev.FilePath,
+		Resolution:  prev.Resolution,
+		Format:      prev.Format,
+		Unchanged:   true,
+		SourceID:    prev.ID,
+		CreatedAt:   now,
+	}
+
+	if err := e.storage.SaveScreenshot(ss); err != nil {
+		return fmt.Errorf("failed to save to database: %w", err)
+	}
+	e.saveWindow(ss.ID, window, bounds)
+
+	logger.Debug("画面无变化，记录心跳: 屏幕 %d -> #%d", prev.ScreenIndex, prev.ID)
+	return nil
+}
+
+// ValidateConfig 校验截屏配置
+func ValidateConfig(cfg models.CaptureConfig) error {
+	if f := cfg.ImageFormat; f != "" {
+		if _, ok := imageformat.Lookup(f); !ok {
+			return fmt.Errorf("不支持的图片格式: %s（可选: %s）", f, strings.Join(imageformat.Names(), ", "))
+		}
+	}
+	if cfg.UnchangedThreshold < 0 || cfg.UnchangedThreshold > 1 {
+		return fmt.Errorf("画面变化阈值必须在 0 到 1 之间")
+	}
+	if cfg.UnchangedMaxAge < 0 {
+		return fmt.Errorf("强制保存间隔不能为负数")
+	}
+	if cfg.IdleThreshold < 0 {
+		return fmt.Errorf("空闲暂停阈值不能为负数")
+	}
+	if cfg.AdaptiveInterval {
+		if cfg.MaxInterval < cfg.Interval {
+			return fmt.Errorf("自适应截屏间隔上限不能小于基础间隔")
+		}
+		if cfg.IntervalGrowth <= 1 {
+			return fmt.Errorf("截屏间隔增长倍数必须大于 1")
+		}
+	}
+	return nil
+}
+
+// encoderFor 按配置的图片格式选择编码器，未知格式回退到 JPEG
+func encoderFor(format string) imageformat.Encoder {
+	if format == "" {
+		return imageformat.Default()
+	}
+	if enc, ok := imageformat.Lookup(format); ok {
+		return enc
+	}
+	logger.Warn("不支持的图片格式 %q，使用 JPEG", format)
+	return imageformat.Default()
+}
+
 // CaptureNow 立即截取一次
 func (e *Engine) CaptureNow(screenIndex int) (*models.Screenshot, error) {
 	n := screenshot.NumActiveDisplays()
@@ -391,13 +630,28 @@ func (e *Engine) CaptureNow(screenIndex int) (*models.Screenshot, error) {
 		return nil, fmt.Errorf("invalid screen index: %d (total: %d)", screenIndex, n)
 	}
 
+	if until := e.PrivateUntil(); !until.IsZero() {
+		return nil, fmt.Errorf("私密模式中（至 %s），已跳过截屏", until.Format("15:04"))
+	}
+
+	// 与定时截屏一样记录跳过的时段，引擎未运行时没有下一轮截屏来结束它，立即结束
+	shot, skip := e.applyPrivacy(e.activeWindow())
+	if skip != nil {
+		if !e.IsRunning() {
+			e.mu.Lock()
+			e.closeGap(&e.privacyGap, clock.Now())
+			e.mu.Unlock()
+		}
+		return nil, fmt.Errorf("前台窗口匹配隐私规则「%s」，已跳过截屏", skip.Name)
+	}
+
 	bounds := screenshot.GetDisplayBounds(screenIndex)
 	img, err := screenshot.CaptureRect(bounds)
 	if err != nil {
 		return nil, fmt.Errorf("screenshot failed: %w", err)
 	}
 
-	if err := e.saveScreenshot(img, screenIndex, bounds); err != nil {
+	if err := e.saveScreenshot(img, screenIndex, bounds, shot); err != nil {
 		return nil, err
 	}
 
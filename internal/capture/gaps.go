@@ -0,0 +1,41 @@
+package capture
+
+import (
+	"time"
+
+	"WorkTrackerAI/pkg/logger"
+	"WorkTrackerAI/pkg/models"
+)
+
+// closeGap 以指定时间结束进行中的暂停时段并清空引用（调用方需持有锁）
+func (e *Engine) closeGap(current **models.CaptureGap, at time.Time) *models.CaptureGap {
+	gap := *current
+	if gap == nil {
+		return nil
+	}
+	*current = nil
+
+	if at.Before(gap.StartTime) {
+		at = gap.StartTime
+	}
+	gap.EndTime = at
+	gap.Open = false
+	e.updateGap(gap)
+	return gap
+}
+
+// closeGaps 结束所有进行中的暂停时段（调用方需持有锁）
+func (e *Engine) closeGaps(at time.Time) {
+	e.closeGap(&e.idleGap, at)
+	e.closeGap(&e.privacyGap, at)
+}
+
+// updateGap 保存暂停时段的结束时间与状态
+func (e *Engine) updateGap(gap *models.CaptureGap) {
+	if gap.ID == 0 {
+		return
+	}
+	if err := e.storage.UpdateCaptureGap(gap); err != nil {
+		logger.Warn("更新暂停时段失败: %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package capture
+
+import (
+	"time"
+
+	"WorkTrackerAI/pkg/clock"
+	"WorkTrackerAI/pkg/logger"
+	"WorkTrackerAI/pkg/models"
+	"WorkTrackerAI/pkg/screenstate"
+)
+
+// idlePaused 检测键鼠空闲，超过阈值时记录空闲时段并返回 true（跳过本次截屏）
+// 空闲时段从最后一次键鼠操作开始，到重新检测到操作时结束
+func (e *Engine) idlePaused(cfg models.CaptureConfig) bool {
+	now := clock.Now()
+	if cfg.IdleThreshold <= 0 {
+		e.endIdle(now)
+		return false
+	}
+
+	idle, err := screenstate.InputIdle()
+	if err != nil {
+		// 不支持的环境每次都会失败，只提示一次
+		e.mu.Lock()
+		warned := e.idleWarned
+		e.idleWarned = true
+		e.mu.Unlock()
+		if warned {
+			logger.Debug("获取键鼠空闲时长失败: %v", err)
+		} else {
+			logger.Warn("获取键鼠空闲时长失败，将不检测离开状态: %v", err)
+		}
+		e.endIdle(now)
+		return false
+	}
+
+	if idle < time.Duration(cfg.IdleThreshold)*time.Second {
+		if gap := e.endIdle(now.Add(-idle)); gap != nil {
+			logger.Info("▶️  检测到键鼠操作，恢复截屏（离开 %v）", gap.EndTime.Sub(gap.StartTime).Round(time.Second))
+		}
+		return false
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.idleGap == nil {
+		e.idleGap = &models.CaptureGap{
+			Kind:      models.GapIdle,
+			StartTime: now.Add(-idle),
+			EndTime:   now,
+			Open:      true,
+		}
+		if err := e.storage.SaveCaptureGap(e.idleGap); err != nil {
+			logger.Warn("保存空闲时段失败: %v", err)
+		}
+		logger.Info("💤 键鼠已 %v 无操作，暂停截屏", idle.Round(time.Second))
+		return true
+	}
+
+	// 持续更新结束时间，异常退出时空闲时段也能保留到最后一次检测
+	e.idleGap.EndTime = now
+	e.updateGap(e.idleGap)
+	return true
+}
+
+// endIdle 结束进行中的空闲时段，返回结束的时段（没有时返回 nil）
+func (e *Engine) endIdle(at time.Time) *models.CaptureGap {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.closeGap(&e.idleGap, at)
+}
+
+// IsIdle 是否因键鼠长时间无操作而暂停截屏
+func (e *Engine) IsIdle() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.idleGap != nil
+}
@@ -0,0 +1,103 @@
+package capture
+
+import (
+	"image"
+	"image/draw"
+	"time"
+
+	"WorkTrackerAI/pkg/activewindow"
+	"WorkTrackerAI/pkg/clock"
+	"WorkTrackerAI/pkg/logger"
+	"WorkTrackerAI/pkg/models"
+	"WorkTrackerAI/pkg/privacy"
+)
+
+// matcher 返回按当前配置编译的隐私规则
+func (e *Engine) matcher() *privacy.Matcher {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.privacy == nil {
+		m, err := privacy.Compile(e.configMgr.GetPrivacy().Rules)
+		if err != nil {
+			logger.Warn("部分隐私规则无效，已忽略: %v", err)
+		}
+		e.privacy = m
+	}
+	return e.privacy
+}
+
+// privacyCheck 按隐私规则检查前台窗口，返回本次截屏的处理方式
+// 需要跳过截屏时返回匹配的规则；涂黑的窗口不记录标题与进程名
+// 涂黑规则匹配但无法获取窗口区域时按跳过处理，遮挡规则照常截屏，在发送给 AI 前遮挡
+func (e *Engine) privacyCheck(window *activewindow.Info) (shotContext, *models.PrivacyRule) {
+	rule := e.matcher().Match(window)
+	if rule == nil {
+		return shotContext{window: window}, nil
+	}
+
+	if rule.Action == models.PrivacyRedact {
+		return shotContext{window: window}, nil
+	}
+	if rule.Action == models.PrivacyBlackout && !window.Bounds.Empty() {
+		logger.Debug("前台窗口匹配隐私规则「%s」，涂黑窗口区域", rule.Name)
+		return shotContext{blackout: window.Bounds}, nil
+	}
+	return shotContext{}, rule
+}
+
+// applyPrivacy 检查隐私规则并记录跳过的截屏，需要跳过本次截屏时返回匹配的规则
+func (e *Engine) applyPrivacy(window *activewindow.Info) (shotContext, *models.PrivacyRule) {
+	shot, rule := e.privacyCheck(window)
+	now := clock.Now()
+	if rule == nil {
+		e.endPrivacySkip(now)
+		return shot, nil
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	// 连续匹配同一规则时延长当前时段，只累计跳过次数
+	if gap := e.privacyGap; gap != nil && gap.Reason == rule.Name {
+		gap.EndTime = now
+		gap.Frames++
+		e.updateGap(gap)
+		return shot, rule
+	}
+
+	e.closeGap(&e.privacyGap, now)
+	e.privacyGap = &models.CaptureGap{
+		Kind:      models.GapPrivacy,
+		StartTime: now,
+		EndTime:   now,
+		Open:      true,
+		Reason:    rule.Name,
+		Frames:    1,
+	}
+	if err := e.storage.SaveCaptureGap(e.privacyGap); err != nil {
+		logger.Warn("保存隐私规则暂停时段失败: %v", err)
+	}
+	logger.Info("🙈 前台窗口匹配隐私规则「%s」，跳过截屏", rule.Name)
+	return shot, rule
+}
+
+// endPrivacySkip 结束进行中的隐私规则暂停时段
+func (e *Engine) endPrivacySkip(at time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if gap := e.closeGap(&e.privacyGap, at); gap != nil {
+		logger.Info("▶️  隐私规则「%s」不再匹配，恢复截屏（跳过 %d 次）", gap.Reason, gap.Frames)
+	}
+}
+
+// blackout 将虚拟桌面坐标中的区域涂黑，img 为 bounds 所示屏幕区域的截图
+func blackout(img *image.RGBA, bounds, area image.Rectangle) {
+	r := area.Intersect(bounds)
+	if r.Empty() {
+		return
+	}
+	r = r.Sub(bounds.Min).Add(img.Bounds().Min)
+	draw.Draw(img, r, image.Black, image.Point{}, draw.Src)
+}
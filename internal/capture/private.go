@@ -0,0 +1,142 @@
+package capture
+
+import (
+	"fmt"
+	"time"
+
+	"WorkTrackerAI/pkg/clock"
+	"WorkTrackerAI/pkg/logger"
+	"WorkTrackerAI/pkg/models"
+	"WorkTrackerAI/pkg/workday"
+)
+
+// PauseTomorrow 私密模式持续到下一个逻辑日开始
+const PauseTomorrow = "tomorrow"
+
+// PauseEnd 计算私密模式的结束时间，option 为时长（如 "15m"、"1h"）或 PauseTomorrow
+func (e *Engine) PauseEnd(option string) (time.Time, error) {
+	now := clock.Now()
+	if option == PauseTomorrow {
+		return workday.Today(e.configMgr.GetSchedule(), now).End, nil
+	}
+
+	d, err := time.ParseDuration(option)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid pause duration %q: %w", option, err)
+	}
+	if d <= 0 {
+		return time.Time{}, fmt.Errorf("pause duration must be positive: %s", option)
+	}
+	return now.Add(d), nil
+}
+
+// Pause 开启私密模式，到指定时间前不截屏，到期后自动恢复
+// 已处于私密模式时只修改结束时间
+func (e *Engine) Pause(until time.Time) (*models.CaptureGap, error) {
+	now := clock.Now()
+	if !until.After(now) {
+		return nil, fmt.Errorf("pause end time must be in the future")
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.private != nil && now.Before(e.private.EndTime) {
+		e.private.EndTime = until
+		e.updateGap(e.private)
+		logger.Info("🔒 私密模式延长至 %s", until.Format("01-02 15:04"))
+		gap := *e.private
+		return &gap, nil
+	}
+	if e.private != nil {
+		e.closeGap(&e.private, e.private.EndTime)
+	}
+
+	// 私密模式期间不再检测离开与隐私规则，已有的暂停时段到此结束
+	e.closeGaps(now)
+
+	e.private = &models.CaptureGap{
+		Kind:      models.GapPrivate,
+		StartTime: now,
+		EndTime:   until,
+		Open:      true,
+	}
+	if err := e.storage.SaveCaptureGap(e.private); err != nil {
+		e.private = nil
+		return nil, fmt.Errorf("failed to save private gap: %w", err)
+	}
+
+	logger.Info("🔒 私密模式已开启，暂停截屏至 %s", until.Format("01-02 15:04"))
+	gap := *e.private
+	return &gap, nil
+}
+
+// Resume 提前结束私密模式，未处于私密模式时返回 false
+func (e *Engine) Resume() bool {
+	now := clock.Now()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.private == nil {
+		return false
+	}
+	if !now.Before(e.private.EndTime) {
+		e.closeGap(&e.private, e.private.EndTime)
+		return false
+	}
+
+	e.closeGap(&e.private, now)
+	logger.Info("▶️  私密模式已手动结束，恢复截屏")
+	return true
+}
+
+// PrivateUntil 私密模式的结束时间，未处于私密模式时返回零值
+func (e *Engine) PrivateUntil() time.Time {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.private == nil || !clock.Now().Before(e.private.EndTime) {
+		return time.Time{}
+	}
+	return e.private.EndTime
+}
+
+// IsPrivate 是否处于私密模式
+func (e *Engine) IsPrivate() bool {
+	return !e.PrivateUntil().IsZero()
+}
+
+// privatePaused 处于私密模式时返回 true（跳过本次截屏），到期时结束私密时段
+func (e *Engine) privatePaused(now time.Time) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.private == nil {
+		return false
+	}
+	if now.Before(e.private.EndTime) {
+		return true
+	}
+
+	gap := e.closeGap(&e.private, e.private.EndTime)
+	logger.Info("▶️  私密模式已到期，恢复截屏（暂停 %v）", gap.EndTime.Sub(gap.StartTime).Round(time.Second))
+	return false
+}
+
+// restorePrivate 恢复上次运行时开启且尚未到期的私密模式，已到期的按计划结束时间结束
+func (e *Engine) restorePrivate() {
+	gap, err := e.storage.GetOpenCaptureGap(models.GapPrivate)
+	if err != nil {
+		logger.Warn("读取私密模式记录失败: %v", err)
+		return
+	}
+	if gap != nil && clock.Now().Before(gap.EndTime) {
+		e.private = gap
+		logger.Info("🔒 私密模式持续至 %s", gap.EndTime.Format("01-02 15:04"))
+		return
+	}
+	if err := e.storage.CloseOpenCaptureGaps(models.GapPrivate); err != nil {
+		logger.Warn("结束遗留的私密模式记录失败: %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package capture
+
+import (
+	"errors"
+	"image"
+
+	"WorkTrackerAI/pkg/activewindow"
+	"WorkTrackerAI/pkg/logger"
+	"WorkTrackerAI/pkg/models"
+	"WorkTrackerAI/pkg/redact"
+)
+
+// ActiveWindowProvider 前台窗口信息来源
+type ActiveWindowProvider interface {
+	ActiveWindow() (*activewindow.Info, error)
+}
+
+// systemWindowProvider 读取当前系统的前台窗口（Windows / X11）
+type systemWindowProvider struct{}
+
+func (systemWindowProvider) ActiveWindow() (*activewindow.Info, error) {
+	return activewindow.Current()
+}
+
+// SetActiveWindowProvider 替换前台窗口来源，传入 nil 时不再记录窗口信息
+func (e *Engine) SetActiveWindowProvider(p ActiveWindowProvider) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.windows = p
+	e.windowWarned = false
+}
+
+// activeWindow 获取截图时的前台窗口，获取失败时返回 nil
+func (e *Engine) activeWindow() *activewindow.Info {
+	e.mu.RLock()
+	provider := e.windows
+	e.mu.RUnlock()
+	if provider == nil {
+		return nil
+	}
+
+	info, err := provider.ActiveWindow()
+	if err == nil {
+		return info
+	}
+	if errors.Is(err, activewindow.ErrNoWindow) {
+		return nil
+	}
+
+	// 不支持的环境每次都会失败，只提示一次
+	e.mu.Lock()
+	warned := e.windowWarned
+	e.windowWarned = true
+	e.mu.Unlock()
+	if warned {
+		logger.Debug("获取前台窗口失败: %v", err)
+	} else {
+		logger.Warn("获取前台窗口失败，截图将不记录窗口信息: %v", err)
+	}
+	return nil
+}
+
+// saveWindow 保存截图关联的前台窗口信息，bounds 为截图对应的屏幕区域
+func (e *Engine) saveWindow(screenshotID int64, info *activewindow.Info, bounds image.Rectangle) {
+	if info == nil {
+		return
+	}
+
+	err := e.storage.SaveScreenshotWindow(&models.ScreenshotWindow{
+		ScreenshotID: screenshotID,
+		Title:        info.Title,
+		ProcessName:  info.ProcessName,
+		PID:          info.PID,
+		Region:       redact.Relative(info.Bounds, bounds),
+	})
+	if err != nil {
+		logger.Warn("保存前台窗口信息失败: %v", err)
+	}
+}
@@ -14,6 +14,9 @@ type Manager struct {
 	config     *models.AppConfig
 	configPath string
 	mu         sync.RWMutex
+
+	subMu       sync.Mutex
+	subscribers []ChangeHandler
 }
 
 // NewManager 创建配置管理器
@@ -43,12 +46,13 @@ func (m *Manager) load() error {
 		return fmt.Errorf("failed to read config: %w", err)
 	}
 
-	var config models.AppConfig
-	if err := json.Unmarshal(data, &config); err != nil {
+	// 以默认配置为基础解析，旧版本配置文件中缺失的新字段保持默认值
+	config := models.DefaultConfig()
+	if err := json.Unmarshal(data, config); err != nil {
 		return fmt.Errorf("failed to parse config: %w", err)
 	}
 
-	m.config = &config
+	m.config = config
 	return nil
 }
 
@@ -85,18 +89,14 @@ func (m *Manager) Get() *models.AppConfig {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	// 返回副本，避免外部修改
-	configCopy := *m.config
-	return &configCopy
+	// 生成深拷贝，避免外部修改切片与 map 字段
+	return cloneConfig(m.config)
 }
 
-// Update 更新配置
+// Update 更新配置（保存后通知订阅者）
 func (m *Manager) Update(updater func(*models.AppConfig)) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	updater(m.config)
-	return m.save() // 使用内部 save() 方法,避免重复加锁
+	_, err := m.UpdateAndApply(updater)
+	return err
 }
 
 // GetCapture 获取截屏配置
@@ -133,3 +133,12 @@ func (m *Manager) GetServer() models.ServerConfig {
 	defer m.mu.RUnlock()
 	return m.config.Server
 }
+
+// GetPrivacy 获取隐私配置
+func (m *Manager) GetPrivacy() models.PrivacyConfig {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	privacy := m.config.Privacy
+	privacy.Rules = append([]models.PrivacyRule(nil), privacy.Rules...)
+	return privacy
+}
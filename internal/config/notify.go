@@ -0,0 +1,128 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"WorkTrackerAI/pkg/models"
+)
+
+// restartRequiredKeys 只在启动时读取、修改后需要重启才能生效的设置项
+var restartRequiredKeys = map[string]bool{
+	"storage.data_dir":         true,
+	"storage.logs_dir":         true,
+	"server.port":              true,
+	"server.host":              true,
+	"server.enable_cors":       true,
+	"server.auto_open_browser": true,
+}
+
+// Change 一次配置变更
+type Change struct {
+	Old  *models.AppConfig
+	New  *models.AppConfig
+	Keys []string // 发生变化的设置项，如 "schedule.start_time"
+}
+
+// Changed 判断指定设置项是否发生变化，以 "." 结尾的参数按前缀匹配（如 "schedule."）
+func (c *Change) Changed(keys ...string) bool {
+	for _, changed := range c.Keys {
+		for _, key := range keys {
+			if changed == key || (strings.HasSuffix(key, ".") && strings.HasPrefix(changed, key)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ChangeHandler 配置变更回调，在配置保存后同步调用
+type ChangeHandler func(change *Change)
+
+// ApplyResult 配置更新结果
+type ApplyResult struct {
+	Applied         []string `json:"applied"`          // 已立即生效的设置项
+	RestartRequired []string `json:"restart_required"` // 需要重启后生效的设置项
+}
+
+// Subscribe 订阅配置变更通知
+func (m *Manager) Subscribe(handler ChangeHandler) {
+	m.subMu.Lock()
+	defer m.subMu.Unlock()
+	m.subscribers = append(m.subscribers, handler)
+}
+
+// UpdateAndApply 更新配置、通知订阅者，并返回各设置项的生效情况
+func (m *Manager) UpdateAndApply(updater func(*models.AppConfig)) (*ApplyResult, error) {
+	m.mu.Lock()
+	oldConfig := cloneConfig(m.config)
+	updater(m.config)
+	newConfig := cloneConfig(m.config)
+	err := m.save()
+	m.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	change := &Change{Old: oldConfig, New: newConfig, Keys: diffKeys(oldConfig, newConfig)}
+	result := &ApplyResult{Applied: []string{}, RestartRequired: []string{}}
+	if len(change.Keys) == 0 {
+		return result, nil
+	}
+
+	m.subMu.Lock()
+	handlers := append([]ChangeHandler(nil), m.subscribers...)
+	m.subMu.Unlock()
+	for _, handler := range handlers {
+		handler(change)
+	}
+
+	for _, key := range change.Keys {
+		if restartRequiredKeys[key] {
+			result.RestartRequired = append(result.RestartRequired, key)
+		} else {
+			result.Applied = append(result.Applied, key)
+		}
+	}
+	return result, nil
+}
+
+// cloneConfig 通过 JSON 往返生成配置的深拷贝
+func cloneConfig(cfg *models.AppConfig) *models.AppConfig {
+	var configCopy models.AppConfig
+	data, _ := json.Marshal(cfg)
+	_ = json.Unmarshal(data, &configCopy)
+	return &configCopy
+}
+
+// diffKeys 比较两份配置，返回发生变化的设置项（"分组.字段" 形式，按字母排序）
+func diffKeys(oldCfg, newCfg *models.AppConfig) []string {
+	oldFields := flattenConfig(oldCfg)
+	newFields := flattenConfig(newCfg)
+
+	var keys []string
+	for key, newValue := range newFields {
+		if oldValue, ok := oldFields[key]; !ok || !reflect.DeepEqual(oldValue, newValue) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// flattenConfig 将配置展开为 "分组.字段" -> 值
+func flattenConfig(cfg *models.AppConfig) map[string]interface{} {
+	var sections map[string]map[string]interface{}
+	data, _ := json.Marshal(cfg)
+	_ = json.Unmarshal(data, &sections)
+
+	fields := make(map[string]interface{})
+	for section, values := range sections {
+		for name, value := range values {
+			fields[section+"."+name] = value
+		}
+	}
+	return fields
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"WorkTrackerAI/pkg/models"
+)
+
+func TestDiffKeys(t *testing.T) {
+	tests := []struct {
+		name   string
+		update func(cfg *models.AppConfig)
+		want   []string
+	}{
+		{
+			name:   "没有变化",
+			update: func(cfg *models.AppConfig) {},
+			want:   nil,
+		},
+		{
+			name:   "单个字段",
+			update: func(cfg *models.AppConfig) { cfg.Schedule.StartTime = "08:30" },
+			want:   []string{"schedule.start_time"},
+		},
+		{
+			name: "多个分组按字母排序",
+			update: func(cfg *models.AppConfig) {
+				cfg.Server.Port++
+				cfg.Capture.Interval++
+				cfg.Capture.Quality--
+			},
+			want: []string{"capture.interval", "capture.quality", "server.port"},
+		},
+		{
+			name:   "切片内容变化",
+			update: func(cfg *models.AppConfig) { cfg.Schedule.WorkDays = append(cfg.Schedule.WorkDays, 6) },
+			want:   []string{"schedule.work_days"},
+		},
+		{
+			name: "嵌套结构整体作为一个设置项",
+			update: func(cfg *models.AppConfig) {
+				cfg.Schedule.Windows = []models.TimeWindow{{Start: "09:00", End: "12:00"}}
+				cfg.Schedule.WeekdayWindows = map[int][]models.TimeWindow{6: {}}
+			},
+			want: []string{"schedule.weekday_windows", "schedule.windows"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldCfg := models.DefaultConfig()
+			newCfg := cloneConfig(oldCfg)
+			tt.update(newCfg)
+
+			if got := diffKeys(oldCfg, newCfg); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffKeys = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChanged(t *testing.T) {
+	change := &Change{Keys: []string{"capture.interval", "schedule.start_time"}}
+
+	tests := []struct {
+		keys []string
+		want bool
+	}{
+		{[]string{"schedule.start_time"}, true},
+		{[]string{"schedule.end_time"}, false},
+		{[]string{"schedule."}, true},
+		{[]string{"schedule"}, false},
+		{[]string{"ai.", "capture.interval"}, true},
+		{[]string{"ai.", "storage."}, false},
+	}
+
+	for _, tt := range tests {
+		if got := change.Changed(tt.keys...); got != tt.want {
+			t.Errorf("Changed(%v) = %v, want %v", tt.keys, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"WorkTrackerAI/internal/ai"
+	"WorkTrackerAI/pkg/clock"
+	"WorkTrackerAI/pkg/models"
+)
+
+const (
+	backlogBaseDelay   = 2 * time.Minute // 首次重试延迟
+	backlogMaxDelay    = time.Hour       // 最大重试间隔
+	backlogMaxAttempts = 12              // 超过该次数后放弃
+	backlogBatchSize   = 5               // 每轮最多处理的时间段数
+)
+
+// enqueueBacklog 将未能完成分析的时间段加入补分析队列
+func (s *Scheduler) enqueueBacklog(start, end time.Time, reason string, cause error) {
+	errMsg := ""
+	if cause != nil {
+		errMsg = cause.Error()
+	}
+
+	if err := s.storageMgr.EnqueueBacklog(start, end, reason, errMsg, clock.Now().Add(backlogBaseDelay)); err != nil {
+		fmt.Printf("⚠️ 加入补分析队列失败: %v\n", err)
+		return
+	}
+	fmt.Printf("📥 时间段 %s - %s 已加入补分析队列 (%s)\n", start.Format("01-02 15:04"), end.Format("15:04"), reason)
+}
+
+// runBacklog 处理补分析队列
+// 先检查 AI 服务是否可达，不可达时不计入重试次数；
+// 某个时间段分析失败后按指数退避推迟，并结束本轮处理，避免在服务异常时连续请求。
+func (s *Scheduler) runBacklog() (string, error) {
+	items, err := s.storageMgr.GetDueBacklog(clock.Now(), backlogBatchSize)
+	if err != nil {
+		fmt.Printf("⚠️ 获取补分析队列失败: %v\n", err)
+		return "", err
+	}
+	if len(items) == 0 {
+		return "", nil
+	}
+
+	if err := s.aiAnalyzer.CheckReachable(); err != nil {
+		fmt.Printf("ℹ️ AI 服务暂不可达，%d 个待补分析时间段稍后重试: %v\n", len(items), err)
+		return fmt.Sprintf("AI 服务暂不可达，%d 个时间段稍后重试", len(items)), nil
+	}
+
+	done := 0
+
+	for _, item := range items {
+		fmt.Printf("🔁 补分析时间段: %s - %s (第 %d 次重试)\n", item.StartTime.Format("01-02 15:04"), item.EndTime.Format("15:04"), item.Attempts+1)
+		_, err := s.aiAnalyzer.AnalyzeSegment(item.StartTime, item.EndTime)
+		if errors.Is(err, ai.ErrAnalysisRunning) {
+			// 其他任务正在分析该时间段，下一轮再确认结果，不计入重试次数
+			continue
+		}
+		if errors.Is(err, ai.ErrAlreadyAnalyzed) {
+			item.Status = models.BacklogStatusDone
+			item.LastError = ""
+			s.updateBacklog(item)
+			done++
+			continue
+		}
+		if err != nil {
+			item.Attempts++
+			item.LastError = err.Error()
+			if !ai.Retryable(err) {
+				// 时间段内已没有截图等无法通过重试解决的错误，直接放弃
+				item.Status = models.BacklogStatusFailed
+				fmt.Printf("❌ 补分析无法完成，已放弃: %s - %s: %v\n", item.StartTime.Format("01-02 15:04"), item.EndTime.Format("15:04"), err)
+				s.updateBacklog(item)
+				continue
+			}
+			if item.Attempts >= backlogMaxAttempts {
+				item.Status = models.BacklogStatusFailed
+				fmt.Printf("❌ 补分析多次失败，已放弃: %s - %s: %v\n", item.StartTime.Format("01-02 15:04"), item.EndTime.Format("15:04"), err)
+			} else {
+				item.NextAttemptAt = clock.Now().Add(backlogDelay(item.Attempts))
+				fmt.Printf("⚠️ 补分析失败，将于 %s 重试: %v\n", item.NextAttemptAt.Format("15:04"), err)
+			}
+			s.updateBacklog(item)
+			return fmt.Sprintf("完成 %d 个时间段", done), err
+		}
+
+		item.Status = models.BacklogStatusDone
+		item.LastError = ""
+		s.updateBacklog(item)
+		done++
+		fmt.Printf("✅ 补分析完成: %s - %s\n", item.StartTime.Format("01-02 15:04"), item.EndTime.Format("15:04"))
+	}
+
+	return fmt.Sprintf("完成 %d 个时间段", done), nil
+}
+
+// updateBacklog 保存补分析队列项状态
+func (s *Scheduler) updateBacklog(item *models.BacklogItem) {
+	if err := s.storageMgr.UpdateBacklog(item); err != nil {
+		fmt.Printf("⚠️ 更新补分析队列失败: %v\n", err)
+	}
+}
+
+// backlogDelay 计算第 attempts 次失败后的重试延迟（指数退避）
+func backlogDelay(attempts int) time.Duration {
+	delay := backlogBaseDelay
+	for i := 1; i < attempts && delay < backlogMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > backlogMaxDelay {
+		delay = backlogMaxDelay
+	}
+	return delay
+}
@@ -0,0 +1,28 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBacklogDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, 2 * time.Minute},
+		{1, 2 * time.Minute},
+		{2, 4 * time.Minute},
+		{3, 8 * time.Minute},
+		{5, 32 * time.Minute},
+		{6, time.Hour},
+		{backlogMaxAttempts, time.Hour},
+		{1000, time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := backlogDelay(tt.attempts); got != tt.want {
+			t.Errorf("backlogDelay(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	"WorkTrackerAI/pkg/clock"
+	"WorkTrackerAI/pkg/workday"
+)
+
+const (
+	resumeCheckInterval = time.Minute     // 唤醒检测周期
+	resumeGapThreshold  = 3 * time.Minute // 两次检测间隔超过该值视为系统曾休眠
+)
+
+// runCatchUp 补分析错过的工作时间段
+// 在回溯范围内查找已结束、与工作时段重叠、有截图但没有总结的时间段，加入补分析队列。
+// 已在队列中的时间段（包括已放弃的）不会重复加入。
+func (s *Scheduler) runCatchUp(trigger string) (string, error) {
+	schedule := s.configMgr.GetSchedule()
+	if schedule.CatchUpLookback <= 0 {
+		return "", nil
+	}
+
+	now := clock.Now()
+	segmenter := workday.NewSegmenter(schedule)
+	current := segmenter.Segment(now)
+	from := segmenter.Segment(now.Add(-time.Duration(schedule.CatchUpLookback) * time.Hour)).Start
+
+	queued := 0
+	for _, seg := range segmenter.Split(from, current.Start) {
+		start, end := seg.Start, seg.End
+		if schedule.Enabled && !workday.OverlapsWorkTime(schedule, start, end) {
+			continue
+		}
+
+		// 已有总结覆盖整个时间段时跳过，只覆盖了前半部分（事件触发分析）时检查剩余部分
+		pending := start
+		covered, err := s.storageMgr.SummaryCoveredUntil(start, end)
+		if err != nil {
+			fmt.Printf("⚠️ 检查历史总结失败: %v\n", err)
+			return "", err
+		}
+		if !covered.IsZero() {
+			if !covered.Before(end) {
+				continue
+			}
+			pending = covered
+		}
+
+		backlogged, err := s.storageMgr.IsBacklogged(start, end)
+		if err != nil {
+			fmt.Printf("⚠️ 检查补分析队列失败: %v\n", err)
+			return "", err
+		}
+		if backlogged {
+			continue
+		}
+
+		count, err := s.storageMgr.CountScreenshots(pending, end)
+		if err != nil {
+			fmt.Printf("⚠️ 统计截图失败: %v\n", err)
+			return "", err
+		}
+		if count == 0 {
+			continue
+		}
+
+		if err := s.storageMgr.EnqueueBacklog(start, end, "catch_up_"+trigger, "", now); err != nil {
+			fmt.Printf("⚠️ 加入补分析队列失败: %v\n", err)
+			return "", err
+		}
+		queued++
+	}
+
+	if queued == 0 {
+		return "", nil
+	}
+	fmt.Printf("📥 补分析检查 (%s): %d 个错过的时间段已加入补分析队列\n", trigger, queued)
+	return fmt.Sprintf("%d 个错过的时间段已加入补分析队列", queued), nil
+}
+
+// checkResume 检测系统是否刚从休眠中唤醒，唤醒后执行补分析检查
+// 通过比较两次检测之间的墙上时间判断（休眠期间定时任务不会执行）
+func (s *Scheduler) checkResume() (string, error) {
+	now := clock.Now().Round(0) // 去掉单调时钟读数，使用墙上时间比较
+
+	s.mu.Lock()
+	last := s.lastResumeCheck
+	s.lastResumeCheck = now
+	s.mu.Unlock()
+
+	if last.IsZero() || now.Sub(last) < resumeGapThreshold {
+		return "", nil
+	}
+
+	fmt.Printf("💤 检测到系统休眠后唤醒 (%s - %s)，检查错过的时间段...\n", last.Format("15:04"), now.Format("15:04"))
+	result, err := s.runCatchUp("resume")
+	if result == "" && err == nil {
+		result = "没有需要补分析的时间段"
+	}
+	return fmt.Sprintf("系统休眠后唤醒 (%s - %s): %s", last.Format("15:04"), now.Format("15:04"), result), err
+}
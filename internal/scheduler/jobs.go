@@ -0,0 +1,330 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"WorkTrackerAI/pkg/clock"
+	"WorkTrackerAI/pkg/models"
+
+	"github.com/robfig/cron/v3"
+)
+
+// 调度任务名称
+const (
+	JobAnalysis         = "analysis"           // 周期分析上一时间段
+	JobSegmentAnalysis  = "segment_analysis"   // 时间段结束后自动分析
+	JobEventAnalysis    = "event_analysis"     // 事件触发分析
+	JobDailyReport      = "daily_report"       // 每日工作日报
+	JobAutoStartCapture = "auto_start_capture" // 工作时段开始时自动启动截图
+	JobAutoStopCapture  = "auto_stop_capture"  // 工作时段结束时自动停止截图
+	JobCleanup          = "cleanup"            // 清理旧数据
+	JobBacklog          = "backlog"            // 处理补分析队列
+	JobResumeCheck      = "resume_check"       // 休眠唤醒检测
+	JobCatchUp          = "catch_up"           // 补分析错过的时间段
+)
+
+// 任务操作错误
+var (
+	ErrJobNotFound = errors.New("任务不存在")
+	ErrJobRunning  = errors.New("任务正在执行")
+)
+
+// jobFunc 任务执行函数，返回执行结果说明
+// 返回空结果且没有错误表示本次无事可做，记为跳过；轮询任务（@every）定时触发的跳过不记录执行历史
+type jobFunc func() (string, error)
+
+// job 命名的调度任务，一个任务可以有多个 cron 触发时间
+type job struct {
+	name        string
+	description string
+	run         jobFunc
+	specs       []string
+	entries     []cron.EntryID
+
+	paused       bool
+	running      bool
+	lastRun      time.Time
+	lastResult   string
+	lastError    string
+	lastDuration time.Duration
+}
+
+// jobRegistry 按名称保存调度任务（保持注册顺序）
+type jobRegistry struct {
+	mu    sync.Mutex
+	jobs  map[string]*job
+	order []string
+}
+
+func newJobRegistry() *jobRegistry {
+	return &jobRegistry{jobs: make(map[string]*job)}
+}
+
+// registerJob 注册命名任务，同名任务已存在时保留其暂停状态与最近结果
+func (s *Scheduler) registerJob(name, description string, run jobFunc) {
+	s.jobs.mu.Lock()
+	defer s.jobs.mu.Unlock()
+
+	if j, ok := s.jobs.jobs[name]; ok {
+		j.description = description
+		j.run = run
+		return
+	}
+	s.jobs.jobs[name] = &job{name: name, description: description, run: run}
+	s.jobs.order = append(s.jobs.order, name)
+}
+
+// scheduleJob 为命名任务添加一个 cron 触发时间
+// guard 不为空时，每次定时触发先判断是否需要执行（手动触发不检查）
+func (s *Scheduler) scheduleJob(name, spec string, guard func() bool) error {
+	s.jobs.mu.Lock()
+	j, ok := s.jobs.jobs[name]
+	s.jobs.mu.Unlock()
+	if !ok {
+		return ErrJobNotFound
+	}
+
+	id, err := s.cron.AddFunc(spec, func() {
+		if guard != nil && !guard() {
+			return
+		}
+		s.execute(j, models.JobTriggerSchedule, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.jobs.mu.Lock()
+	j.specs = append(j.specs, spec)
+	j.entries = append(j.entries, id)
+	s.jobs.mu.Unlock()
+	return nil
+}
+
+// unscheduleJobs 移除任务的所有 cron 触发时间（任务本身及其状态保留）
+func (s *Scheduler) unscheduleJobs(names ...string) {
+	s.jobs.mu.Lock()
+	defer s.jobs.mu.Unlock()
+
+	for _, name := range names {
+		j, ok := s.jobs.jobs[name]
+		if !ok {
+			continue
+		}
+		for _, id := range j.entries {
+			s.cron.Remove(id)
+		}
+		j.specs = nil
+		j.entries = nil
+	}
+}
+
+// execute 执行任务并记录结果，run 为空时使用任务注册的执行函数
+// 同一任务不会并发执行；已暂停的任务只能手动触发
+func (s *Scheduler) execute(j *job, trigger string, run jobFunc) {
+	s.jobs.mu.Lock()
+	if j.running || (j.paused && trigger == models.JobTriggerSchedule) {
+		s.jobs.mu.Unlock()
+		return
+	}
+	j.running = true
+	if run == nil {
+		run = j.run
+	}
+	s.jobs.mu.Unlock()
+
+	started := clock.Now()
+	begin := time.Now()
+	result, err := runJob(run)
+	duration := time.Since(begin)
+
+	// 没有结果也没有错误表示本次条件不满足而跳过，不覆盖最近一次结果
+	status := models.JobRunSuccess
+	switch {
+	case err != nil:
+		status = models.JobRunFailed
+	case result == "":
+		status = models.JobRunSkipped
+	}
+
+	s.jobs.mu.Lock()
+	j.running = false
+	// 轮询任务几乎每次都无事可做，定时触发的跳过不写入执行历史，避免淹没真正的执行记录
+	persist := status != models.JobRunSkipped || trigger != models.JobTriggerSchedule || !j.polling()
+	if status != models.JobRunSkipped {
+		j.lastRun = started
+		j.lastResult = result
+		j.lastError = ""
+		if err != nil {
+			j.lastError = err.Error()
+		}
+		j.lastDuration = duration
+	}
+	s.jobs.mu.Unlock()
+
+	if !persist {
+		return
+	}
+
+	record := &models.JobRun{
+		JobName:    j.name,
+		Trigger:    trigger,
+		StartedAt:  started,
+		DurationMs: duration.Milliseconds(),
+		Status:     status,
+		Result:     result,
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	if err := s.storageMgr.SaveJobRun(record); err != nil {
+		fmt.Printf("⚠️ 保存任务执行记录失败: %v\n", err)
+	}
+}
+
+// polling 任务是否只按固定间隔（@every）轮询触发（调用方需持有锁）
+func (j *job) polling() bool {
+	if len(j.specs) == 0 {
+		return false
+	}
+	for _, spec := range j.specs {
+		if !strings.HasPrefix(spec, "@every") {
+			return false
+		}
+	}
+	return true
+}
+
+// runJob 执行任务函数，任务 panic 时转换为错误，避免影响调度器
+func runJob(run jobFunc) (result string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("任务异常: %v", r)
+		}
+	}()
+	return run()
+}
+
+// Jobs 获取所有调度任务的状态（按注册顺序）
+func (s *Scheduler) Jobs() []models.JobInfo {
+	s.jobs.mu.Lock()
+	defer s.jobs.mu.Unlock()
+
+	infos := make([]models.JobInfo, 0, len(s.jobs.order))
+	for _, name := range s.jobs.order {
+		infos = append(infos, s.jobInfo(s.jobs.jobs[name]))
+	}
+	return infos
+}
+
+// Job 获取单个调度任务的状态
+func (s *Scheduler) Job(name string) (models.JobInfo, error) {
+	s.jobs.mu.Lock()
+	defer s.jobs.mu.Unlock()
+
+	j, ok := s.jobs.jobs[name]
+	if !ok {
+		return models.JobInfo{}, ErrJobNotFound
+	}
+	return s.jobInfo(j), nil
+}
+
+// jobInfo 汇总任务状态，调用方需持有 s.jobs.mu
+// 下次执行时间取所有触发时间中最早的一个；上次执行时间取定时触发与实际执行中较晚的一个
+func (s *Scheduler) jobInfo(j *job) models.JobInfo {
+	info := models.JobInfo{
+		Name:         j.name,
+		Description:  j.description,
+		Specs:        append([]string{}, j.specs...),
+		Paused:       j.paused,
+		Running:      j.running,
+		LastResult:   j.lastResult,
+		LastError:    j.lastError,
+		LastDuration: j.lastDuration.Milliseconds(),
+	}
+	sort.Strings(info.Specs)
+
+	var next, prev time.Time
+	if !j.lastRun.IsZero() {
+		prev = j.lastRun
+	}
+	for _, id := range j.entries {
+		entry := s.cron.Entry(id)
+		if !entry.Next.IsZero() && (next.IsZero() || entry.Next.Before(next)) {
+			next = entry.Next
+		}
+		if entry.Prev.After(prev) {
+			prev = entry.Prev
+		}
+	}
+	if !next.IsZero() && !j.paused {
+		info.NextRun = &next
+	}
+	if !prev.IsZero() {
+		info.PrevRun = &prev
+	}
+	return info
+}
+
+// executeByName 按名称执行任务（用于调度器内部触发，如启动时补分析）
+func (s *Scheduler) executeByName(name, trigger string, run jobFunc) {
+	s.jobs.mu.Lock()
+	j, ok := s.jobs.jobs[name]
+	s.jobs.mu.Unlock()
+	if ok {
+		s.execute(j, trigger, run)
+	}
+}
+
+// TriggerJob 立即在后台执行任务（忽略暂停状态与触发条件）
+func (s *Scheduler) TriggerJob(name string) error {
+	s.jobs.mu.Lock()
+	j, ok := s.jobs.jobs[name]
+	running := ok && j.running
+	s.jobs.mu.Unlock()
+
+	if !ok {
+		return ErrJobNotFound
+	}
+	if running {
+		return ErrJobRunning
+	}
+
+	fmt.Printf("▶️ 手动触发任务: %s\n", name)
+	go s.execute(j, models.JobTriggerManual, nil)
+	return nil
+}
+
+// PauseJob 暂停任务的定时执行
+func (s *Scheduler) PauseJob(name string) error {
+	return s.setJobPaused(name, true)
+}
+
+// ResumeJob 恢复任务的定时执行
+func (s *Scheduler) ResumeJob(name string) error {
+	return s.setJobPaused(name, false)
+}
+
+// setJobPaused 设置任务暂停状态
+func (s *Scheduler) setJobPaused(name string, paused bool) error {
+	s.jobs.mu.Lock()
+	defer s.jobs.mu.Unlock()
+
+	j, ok := s.jobs.jobs[name]
+	if !ok {
+		return ErrJobNotFound
+	}
+	j.paused = paused
+
+	if paused {
+		fmt.Printf("⏸️ 任务已暂停: %s\n", name)
+	} else {
+		fmt.Printf("▶️ 任务已恢复: %s\n", name)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"WorkTrackerAI/pkg/clock"
+	"WorkTrackerAI/pkg/models"
+
+	"github.com/robfig/cron/v3"
+)
+
+// manualRun 手动推进模式的状态
+type manualRun struct {
+	last time.Time                  // 上次推进到的时间
+	next map[cron.EntryID]time.Time // 各触发时间的下次到期时间
+}
+
+// StartManual 启动调度器但不启动后台定时器，由调用方推进时钟后调用 RunDue 执行到期任务
+// 用于配合模拟时钟快速运行（如一分钟跑完一个工作日）
+func (s *Scheduler) StartManual() error {
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return fmt.Errorf("scheduler already running")
+	}
+	if err := s.addJobs(); err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	s.running = true
+	s.manual = &manualRun{
+		last: clock.Now(),
+		next: make(map[cron.EntryID]time.Time),
+	}
+	s.mu.Unlock()
+
+	s.executeByName(JobCatchUp, models.JobTriggerStartup, func() (string, error) {
+		return s.runCatchUp(models.JobTriggerStartup)
+	})
+
+	fmt.Println("⏰ 任务调度器已启动 (手动推进模式)")
+	return nil
+}
+
+// RunDue 同步执行上次推进之后到当前时钟时间之间到期的任务，返回执行的触发次数
+// 两次推进之间同一触发时间多次到期时只执行一次，推进步长应不大于最短的任务周期
+func (s *Scheduler) RunDue() int {
+	now := clock.Now()
+
+	s.mu.Lock()
+	if s.manual == nil {
+		s.mu.Unlock()
+		return 0
+	}
+
+	var due []cron.Entry
+	next := make(map[cron.EntryID]time.Time)
+	for _, entry := range s.cron.Entries() {
+		at, ok := s.manual.next[entry.ID]
+		if !ok {
+			// 新添加的触发时间（如配置变更后重新添加）从上次推进的时间开始计算
+			at = entry.Schedule.Next(s.manual.last)
+		}
+		if !at.After(now) {
+			due = append(due, entry)
+			at = entry.Schedule.Next(now)
+		}
+		next[entry.ID] = at
+	}
+	s.manual.next = next
+	s.manual.last = now
+	s.mu.Unlock()
+
+	// 按添加顺序执行，执行期间不持有锁（任务本身可能需要加锁）
+	sort.Slice(due, func(i, j int) bool { return due[i].ID < due[j].ID })
+	for _, entry := range due {
+		entry.WrappedJob.Run()
+	}
+	return len(due)
+}
@@ -1,7 +1,9 @@
 package scheduler
 
 import (
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -9,29 +11,15 @@ import (
 	"WorkTrackerAI/internal/ai"
 	"WorkTrackerAI/internal/config"
 	"WorkTrackerAI/internal/storage"
+	"WorkTrackerAI/pkg/clock"
+	"WorkTrackerAI/pkg/models"
+	"WorkTrackerAI/pkg/workday"
 
 	"github.com/robfig/cron/v3"
 )
 
-// workDaysToCron 将工作日数组转换为cron表达式的星期部分
-// workDays: [0,1,2,3,4,5,6] 其中0=周日，1=周一，...，6=周六
-// 返回: "0,1,2,3,4,5,6" 或 "*" (如果全选)
-func workDaysToCron(workDays []int) string {
-	if len(workDays) == 0 {
-		return "*" // 空数组视为全选
-	}
-	if len(workDays) == 7 {
-		return "*" // 全部7天
-	}
-	
-	// 转换为字符串数组并排序
-	dayStrs := make([]string, len(workDays))
-	for i, day := range workDays {
-		dayStrs[i] = fmt.Sprintf("%d", day)
-	}
-	
-	return strings.Join(dayStrs, ",")
-}
+// segmentAnalysisDelay 时间段结束后等待多久再自动分析（等待最后的截图写入）
+const segmentAnalysisDelay = 5 * time.Minute
 
 // CaptureEngine 定义截图引擎接口，避免循环依赖
 type CaptureEngine interface {
@@ -49,8 +37,19 @@ type Scheduler struct {
 	captureEng CaptureEngine
 	mu         sync.Mutex
 	running    bool
+
+	jobs            *jobRegistry // 命名任务及其状态
+	lastResumeCheck time.Time    // 上次唤醒检测的墙上时间
+	manual          *manualRun   // 手动推进模式（模拟运行）的状态，为空表示使用后台定时器
+
+	// 事件触发分析状态（只在事件触发任务中访问）
+	triggerRetryAt time.Time             // 分析失败后在该时间前不再触发
+	baseline       screenshotFingerprint // 待分析部分基准截图的指纹缓存
 }
 
+// scheduleDependentJobs 依赖工作时间配置的任务，配置变更时重新添加触发时间
+var scheduleDependentJobs = []string{JobAnalysis, JobDailyReport, JobAutoStartCapture, JobAutoStopCapture}
+
 // NewScheduler 创建任务调度器
 func NewScheduler(
 	configMgr *config.Manager,
@@ -58,13 +57,33 @@ func NewScheduler(
 	aiAnalyzer *ai.Analyzer,
 	captureEng CaptureEngine,
 ) *Scheduler {
-	return &Scheduler{
+	s := &Scheduler{
 		cron:       cron.New(),
 		configMgr:  configMgr,
 		storageMgr: storageMgr,
 		aiAnalyzer: aiAnalyzer,
 		captureEng: captureEng,
+		jobs:       newJobRegistry(),
 	}
+	s.registerJobs()
+	configMgr.Subscribe(s.onConfigChange)
+	return s
+}
+
+// registerJobs 注册所有命名任务
+func (s *Scheduler) registerJobs() {
+	s.registerJob(JobAnalysis, "周期分析上一个已结束的时间段", s.runAnalysis)
+	s.registerJob(JobSegmentAnalysis, "时间段结束后自动分析上一时间段", s.runPreviousSegmentAnalysis)
+	s.registerJob(JobEventAnalysis, "事件触发分析（截图数量、屏幕无活动、画面变化）", s.runEventTriggers)
+	s.registerJob(JobDailyReport, "工作结束前生成每日工作日报", s.runDailyReport)
+	s.registerJob(JobAutoStartCapture, "工作时段开始时自动启动截图", s.autoStartCapture)
+	s.registerJob(JobAutoStopCapture, "工作时段结束时自动停止截图", s.autoStopCapture)
+	s.registerJob(JobCleanup, "清理过期截图与任务执行记录", s.runCleanup)
+	s.registerJob(JobBacklog, "处理补分析队列", s.runBacklog)
+	s.registerJob(JobResumeCheck, "检测系统休眠唤醒并补分析", s.checkResume)
+	s.registerJob(JobCatchUp, "补分析回溯范围内错过的时间段", func() (string, error) {
+		return s.runCatchUp(models.JobTriggerManual)
+	})
 }
 
 // Start 启动调度器
@@ -76,14 +95,64 @@ func (s *Scheduler) Start() error {
 		return fmt.Errorf("scheduler already running")
 	}
 
-	// 添加周期性 AI 分析任务
+	if err := s.addJobs(); err != nil {
+		return err
+	}
+
+	s.cron.Start()
+	s.running = true
+
+	// 启动时补分析回溯范围内错过的时间段
+	go s.executeByName(JobCatchUp, models.JobTriggerStartup, func() (string, error) {
+		return s.runCatchUp(models.JobTriggerStartup)
+	})
+
+	fmt.Printf("⏰ 任务调度器已启动 (AI分析间隔: %d分钟)\n", s.configMgr.GetSchedule().AnalysisInterval)
+	return nil
+}
+
+// addJobs 为所有命名任务添加触发时间
+func (s *Scheduler) addJobs() error {
+	// 添加依赖工作时间配置的任务
+	if err := s.addScheduleJobs(); err != nil {
+		return err
+	}
+
+	// 添加清理任务（每天凌晨 3 点）
+	if err := s.scheduleJob(JobCleanup, "0 3 * * *", nil); err != nil {
+		return fmt.Errorf("failed to add cleanup job: %w", err)
+	}
+
+	// 自动分析上一时间段（时间段结束后第5分钟执行，更稳妥）
+	if err := s.scheduleJob(JobSegmentAnalysis, "* * * * *", s.segmentAnalysisDue); err != nil {
+		return fmt.Errorf("failed to add segment analysis job: %w", err)
+	}
+
+	// 按事件触发分析（新增截图、屏幕无活动、画面变化），与定时分析同时生效
+	if err := s.scheduleJob(JobEventAnalysis, fmt.Sprintf("@every %s", triggerCheckInterval), nil); err != nil {
+		return fmt.Errorf("failed to add event analysis job: %w", err)
+	}
+
+	// 每分钟处理补分析队列（LLM 恢复可用后补齐失败的时间段）
+	if err := s.scheduleJob(JobBacklog, "@every 1m", nil); err != nil {
+		return fmt.Errorf("failed to add backlog job: %w", err)
+	}
+
+	// 定期检测系统休眠唤醒，唤醒后补分析错过的时间段
+	if err := s.scheduleJob(JobResumeCheck, fmt.Sprintf("@every %s", resumeCheckInterval), nil); err != nil {
+		return fmt.Errorf("failed to add resume check job: %w", err)
+	}
+
+	return nil
+}
+
+// addScheduleJobs 添加依赖工作时间配置的任务（周期分析、日报、自动启停截图）
+func (s *Scheduler) addScheduleJobs() error {
 	schedule := s.configMgr.GetSchedule()
-	analysisInterval := schedule.AnalysisInterval // 分钟
 
 	// 每 N 分钟执行一次分析
-	cronExpr := fmt.Sprintf("@every %dm", analysisInterval)
-	_, err := s.cron.AddFunc(cronExpr, s.runAnalysis)
-	if err != nil {
+	cronExpr := fmt.Sprintf("@every %dm", schedule.AnalysisInterval)
+	if err := s.scheduleJob(JobAnalysis, cronExpr, nil); err != nil {
 		return fmt.Errorf("failed to add analysis job: %w", err)
 	}
 
@@ -102,23 +171,30 @@ func (s *Scheduler) Start() error {
 		fmt.Printf("⚠️ 添加自动停止截图任务失败: %v\n", err)
 	}
 
-	// 添加清理任务（每天凌晨 3 点）
-	_, err = s.cron.AddFunc("0 3 * * *", s.runCleanup)
-	if err != nil {
-		return fmt.Errorf("failed to add cleanup job: %w", err)
+	return nil
+}
+
+// onConfigChange 工作时间或分析间隔变更时重新添加相关任务，无需重启
+func (s *Scheduler) onConfigChange(change *config.Change) {
+	if !change.Changed("schedule.start_time", "schedule.end_time", "schedule.work_days", "schedule.analysis_interval",
+		"schedule.windows", "schedule.weekday_windows", "schedule.day_boundary") {
+		return
 	}
 
-	// 每小时自动检查上一时间段是否需要分析（整点过5分钟执行，更稳妥）
-	_, err = s.cron.AddFunc("5 * * * *", s.runHourlyPreviousSegmentAnalysis)
-	if err != nil {
-		return fmt.Errorf("failed to add hourly analysis job: %w", err)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.running {
+		return
 	}
 
-	s.cron.Start()
-	s.running = true
+	s.unscheduleJobs(scheduleDependentJobs...)
 
-	fmt.Printf("⏰ 任务调度器已启动 (AI分析间隔: %d分钟)\n", analysisInterval)
-	return nil
+	if err := s.addScheduleJobs(); err != nil {
+		fmt.Printf("⚠️ 重新添加调度任务失败: %v\n", err)
+		return
+	}
+	fmt.Printf("🔄 工作时间配置已更新，调度任务已重新添加 (AI分析间隔: %d分钟)\n", change.New.Schedule.AnalysisInterval)
 }
 
 // Stop 停止调度器
@@ -132,6 +208,7 @@ func (s *Scheduler) Stop() {
 
 	s.cron.Stop()
 	s.running = false
+	s.manual = nil
 	fmt.Println("⏰ 任务调度器已停止")
 }
 
@@ -142,185 +219,175 @@ func (s *Scheduler) IsRunning() bool {
 	return s.running
 }
 
-// runAnalysis 执行 AI 分析（使用整点边界）
-func (s *Scheduler) runAnalysis() {
+// runAnalysis 执行 AI 分析（分析上一个已结束的时间段）
+func (s *Scheduler) runAnalysis() (string, error) {
 	fmt.Println("🤖 开始 AI 分析任务...")
 
-	// 使用整点边界：从上一个整点到当前整点
-	now := time.Now()
-	currentHour := time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), 0, 0, 0, now.Location())
-	prevHour := currentHour.Add(-1 * time.Hour)
+	// 按配置的时长与对齐方式取上一个时间段
+	schedule := s.configMgr.GetSchedule()
+	seg := workday.NewSegmenter(schedule).Previous(clock.Now())
+	period := fmt.Sprintf("%s - %s", seg.Start.Format("15:04"), seg.End.Format("15:04"))
 
-	// 检查该时间段是否已存在总结，避免重复分析
-	hasSummary, err := s.storageMgr.HasWorkSummaryForRange(prevHour, currentHour)
+	// 不在工作时间或没有截图的时间段无需分析，也不加入补分析队列
+	if schedule.Enabled && !workday.OverlapsWorkTime(schedule, seg.Start, seg.End) {
+		fmt.Printf("ℹ️ 时间段 %s 不在工作时间范围内，跳过分析\n", period)
+		return fmt.Sprintf("%s 不在工作时间范围内，跳过分析", period), nil
+	}
+	count, err := s.storageMgr.CountScreenshots(seg.Start, seg.End)
 	if err != nil {
-		fmt.Printf("⚠️ 检查历史总结失败: %v\n", err)
-		return
+		fmt.Printf("⚠️ 获取截图失败: %v\n", err)
+		return "", err
 	}
-	if hasSummary {
-		fmt.Printf("ℹ️ 时间段 %s - %s 已存在总结，跳过分析\n", prevHour.Format("15:04"), currentHour.Format("15:04"))
-		return
+	if count == 0 {
+		fmt.Printf("ℹ️ 时间段 %s 内没有截图，跳过分析\n", period)
+		return fmt.Sprintf("%s 内没有截图，跳过分析", period), nil
 	}
 
-	summary, err := s.aiAnalyzer.AnalyzePeriod(prevHour, currentHour)
+	// 占用该时间段后再检查是否已存在总结，避免与其他分析重复
+	summary, err := s.aiAnalyzer.AnalyzeSegment(seg.Start, seg.End)
+	if errors.Is(err, ai.ErrAlreadyAnalyzed) || errors.Is(err, ai.ErrAnalysisRunning) {
+		fmt.Printf("ℹ️ %s: %v，跳过分析\n", period, err)
+		return fmt.Sprintf("%s: %v，跳过分析", period, err), nil
+	}
 	if err != nil {
 		fmt.Printf("❌ AI 分析失败: %v\n", err)
-		return
+		// 只有 LLM 调用或解析失败这类临时错误才值得稍后重试
+		if ai.Retryable(err) {
+			s.enqueueBacklog(seg.Start, seg.End, "analysis_failed", err)
+		}
+		return "", err
 	}
 
-	fmt.Printf("✅ AI 分析完成: %s - %s: %s\n", prevHour.Format("15:04"), currentHour.Format("15:04"), summary.Summary)
+	fmt.Printf("✅ AI 分析完成: %s: %s\n", period, summary.Summary)
+	return fmt.Sprintf("%s 分析完成", period), nil
 }
 
 // runCleanup 执行清理任务
-func (s *Scheduler) runCleanup() {
+func (s *Scheduler) runCleanup() (string, error) {
 	fmt.Println("🧹 开始清理旧数据...")
 
 	storageCfg := s.configMgr.GetStorage()
 	deleted, err := s.storageMgr.DeleteOldScreenshots(storageCfg.RetentionDays)
 	if err != nil {
 		fmt.Printf("❌ 清理失败: %v\n", err)
-		return
+		return "", err
+	}
+
+	// 任务执行记录与截图使用相同的保留天数
+	runs, err := s.storageMgr.DeleteOldJobRuns(storageCfg.RetentionDays)
+	if err != nil {
+		fmt.Printf("⚠️ 清理任务执行记录失败: %v\n", err)
 	}
 
 	fmt.Printf("✅ 清理完成，删除了 %d 个旧截图\n", deleted)
+	return fmt.Sprintf("删除了 %d 个旧截图、%d 条任务执行记录", deleted, runs), nil
+}
+
+// segmentAnalysisDue 判断当前是否为上一时间段结束后的宽限时间点
+// 只在该时间点执行一次（错过的时间段由补分析处理）
+func (s *Scheduler) segmentAnalysisDue() bool {
+	now := clock.Now()
+	prev := workday.NewSegmenter(s.configMgr.GetSchedule()).Previous(now)
+	return now.Truncate(time.Minute).Equal(prev.End.Add(segmentAnalysisDelay))
 }
 
-// runHourlyPreviousSegmentAnalysis 每小时自动分析上一个整点时间段
+// runPreviousSegmentAnalysis 自动分析上一个时间段
 // 行为：
-//   - 每小时的第 5 分钟执行（例如 16:05）；
-//   - 计算上一小时段 [H-1:00, H:00)；
-//   - 如果该段结束时间在配置的工作结束时间内；
-//   - 且该段尚无工作总结；
+//   - 每分钟检查一次，只在上一时间段结束后第 5 分钟执行（例如 16:05），手动触发时立即执行；
+//   - 时间段长度与对齐方式由配置决定（默认按整点切分 1 小时）；
+//   - 如果该段与配置的工作时段有重叠；
 //   - 且该段内有截图；
+//   - 且该段尚无工作总结、也没有其他分析正在进行（占用时间段后检查）；
 //   - 则调用 AI 对该段进行一次分析，并保存结果。
-func (s *Scheduler) runHourlyPreviousSegmentAnalysis() {
-	fmt.Println("⏰ 每小时自动检查上一时间段是否需要分析...")
-
+func (s *Scheduler) runPreviousSegmentAnalysis() (string, error) {
 	schedule := s.configMgr.GetSchedule()
-	if !schedule.Enabled {
-		fmt.Println("ℹ️ 工作时间限制未启用，跳过自动整点分析")
-		return
-	}
-
-	now := time.Now()
-
-	// 解析工作时间配置
-	startParts, err := time.Parse("15:04", schedule.StartTime)
-	if err != nil {
-		fmt.Printf("⚠️ 无效的开始时间配置: %v\n", err)
-		return
-	}
-	endParts, err := time.Parse("15:04", schedule.EndTime)
-	if err != nil {
-		fmt.Printf("⚠️ 无效的结束时间配置: %v\n", err)
-		return
-	}
+	prev := workday.NewSegmenter(schedule).Previous(clock.Now())
+	period := fmt.Sprintf("%s - %s", prev.Start.Format("15:04"), prev.End.Format("15:04"))
 
-	workStart := time.Date(now.Year(), now.Month(), now.Day(), startParts.Hour(), startParts.Minute(), 0, 0, now.Location())
-	workEnd := time.Date(now.Year(), now.Month(), now.Day(), endParts.Hour(), endParts.Minute(), 0, 0, now.Location())
+	fmt.Println("⏰ 自动检查上一时间段是否需要分析...")
 
-	// 计算上一小时段 [prevStart, prevEnd)
-	prevEnd := time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), 0, 0, 0, now.Location())
-	prevStart := prevEnd.Add(-1 * time.Hour)
-
-	// 如果上一段结束时间超出工作结束时间，则不再自动分析
-	if prevEnd.After(workEnd) {
-		fmt.Println("ℹ️ 上一个整点已超过配置的工作结束时间，跳过自动分析")
-		return
-	}
-	// 如果上一段开始时间早于工作开始时间，也不分析（例如早上还没到上班时间）
-	if prevEnd.Before(workStart) || prevStart.Before(workStart) {
-		fmt.Println("ℹ️ 上一时间段尚未进入工作时间范围，跳过自动分析")
-		return
+	if !schedule.Enabled {
+		fmt.Println("ℹ️ 工作时间限制未启用，跳过自动分析")
+		return "工作时间限制未启用，跳过自动分析", nil
 	}
 
-	// 检查该时间段是否已存在总结，避免重复分析
-	hasSummary, err := s.storageMgr.HasWorkSummaryForRange(prevStart, prevEnd)
-	if err != nil {
-		fmt.Printf("⚠️ 检查历史总结失败: %v\n", err)
-		return
-	}
-	if hasSummary {
-		fmt.Printf("ℹ️ 时间段 %s - %s 已存在总结，跳过自动分析\n", prevStart.Format("15:04"), prevEnd.Format("15:04"))
-		return
+	// 上一段与工作时段没有重叠时不分析（例如早上还没到上班时间、午休时间）
+	if !workday.OverlapsWorkTime(schedule, prev.Start, prev.End) {
+		fmt.Println("ℹ️ 上一时间段不在配置的工作时间范围内，跳过自动分析")
+		return fmt.Sprintf("%s 不在工作时间范围内，跳过自动分析", period), nil
 	}
 
 	// 检查该段内是否有截图
-	screenshots, err := s.storageMgr.GetScreenshots(prevStart, prevEnd)
+	count, err := s.storageMgr.CountScreenshots(prev.Start, prev.End)
 	if err != nil {
 		fmt.Printf("⚠️ 获取截图失败: %v\n", err)
-		return
+		return "", err
 	}
-	if len(screenshots) == 0 {
-		fmt.Printf("ℹ️ 时间段 %s - %s 内没有截图，跳过自动分析\n", prevStart.Format("15:04"), prevEnd.Format("15:04"))
-		return
+	if count == 0 {
+		fmt.Printf("ℹ️ 时间段 %s 内没有截图，跳过自动分析\n", period)
+		return fmt.Sprintf("%s 内没有截图，跳过自动分析", period), nil
 	}
 
 	// 调用 AI 进行分析
-	fmt.Printf("🤖 自动分析上一时间段: %s - %s...\n", prevStart.Format("15:04"), prevEnd.Format("15:04"))
-	summary, err := s.aiAnalyzer.AnalyzePeriod(prevStart, prevEnd)
+	fmt.Printf("🤖 自动分析上一时间段: %s...\n", period)
+	summary, err := s.aiAnalyzer.AnalyzeSegment(prev.Start, prev.End)
+	if errors.Is(err, ai.ErrAlreadyAnalyzed) || errors.Is(err, ai.ErrAnalysisRunning) {
+		fmt.Printf("ℹ️ %s: %v，跳过自动分析\n", period, err)
+		return fmt.Sprintf("%s: %v，跳过自动分析", period, err), nil
+	}
 	if err != nil {
-		fmt.Printf("❌ 自动整点分析失败: %v\n", err)
-		return
+		fmt.Printf("❌ 自动分析失败: %v\n", err)
+		if ai.Retryable(err) {
+			s.enqueueBacklog(prev.Start, prev.End, "analysis_failed", err)
+		}
+		return "", err
 	}
 
-	fmt.Printf("✅ 自动整点分析完成：%s - %s，摘要：%s\n", prevStart.Format("15:04"), prevEnd.Format("15:04"), summary.Summary)
+	fmt.Printf("✅ 自动分析完成：%s，摘要：%s\n", period, summary.Summary)
+	return fmt.Sprintf("%s 分析完成", period), nil
 }
 
-// addDailyReportJob 添加每日工作日报任务
+// addDailyReportJob 添加每日工作日报任务（每个工作日最后一个工作时段结束前10分钟）
 func (s *Scheduler) addDailyReportJob() error {
-	schedule := s.configMgr.GetSchedule()
-
-	// 解析工作结束时间
-	endTime, err := time.Parse("15:04", schedule.EndTime)
-	if err != nil {
-		return fmt.Errorf("无效的结束时间格式: %w", err)
-	}
-
-	// 计算工作结束前10分钟的时间
-	reportTime := endTime.Add(-10 * time.Minute)
-	hour := reportTime.Hour()
-	minute := reportTime.Minute()
-
-	// 创建 cron 表达式，使用配置的工作日
-	// 例如：17:50 工作日1,2,3,4,5 -> "50 17 * * 1,2,3,4,5"
-	weekDays := workDaysToCron(schedule.WorkDays)
-	cronExpr := fmt.Sprintf("%d %d * * %s", minute, hour, weekDays)
-
-	_, err = s.cron.AddFunc(cronExpr, s.runDailyReport)
+	times, err := s.addWindowJobs(JobDailyReport, func(windows []workday.Range) []time.Time {
+		return []time.Time{windows[len(windows)-1].End.Add(-10 * time.Minute)}
+	})
 	if err != nil {
 		return fmt.Errorf("failed to add daily report job: %w", err)
 	}
 
-	fmt.Printf("📊 每日工作日报任务已添加 (工作日 %02d:%02d 生成)\n", hour, minute)
+	fmt.Printf("📊 每日工作日报任务已添加 (工作日 %s 生成)\n", strings.Join(times, ", "))
 	return nil
 }
 
 // runDailyReport 生成每日工作日报
-func (s *Scheduler) runDailyReport() {
+func (s *Scheduler) runDailyReport() (string, error) {
 	fmt.Println("📊 开始生成每日工作日报...")
 
+	// 当前所在的工作日（逻辑日）第一个工作时段开始到最后一个工作时段结束；
+	// 夜班在次日凌晨生成日报时属于前一逻辑日
 	schedule := s.configMgr.GetSchedule()
-
-	// 解析工作开始和结束时间
-	now := time.Now()
-	startTimeStr := schedule.StartTime
-	endTimeStr := schedule.EndTime
-
-	// 构造今天的工作开始和结束时间
-	startParts, _ := time.Parse("15:04", startTimeStr)
-	endParts, _ := time.Parse("15:04", endTimeStr)
-
-	start := time.Date(now.Year(), now.Month(), now.Day(),
-		startParts.Hour(), startParts.Minute(), 0, 0, now.Location())
-	end := time.Date(now.Year(), now.Month(), now.Day(),
-		endParts.Hour(), endParts.Minute(), 0, 0, now.Location())
+	now := clock.Now()
+	day := workday.LogicalDay(schedule, now)
+	var bounds workday.Range
+	found := false
+	for _, date := range []time.Time{day, day.AddDate(0, 0, -1)} {
+		if b, ok := workday.DayBounds(schedule, date); ok && !now.Before(b.Start) && !now.After(b.End) {
+			bounds, found = b, true
+			break
+		}
+	}
+	if !found {
+		fmt.Println("ℹ️ 当前不在任何工作日的工作时间内，跳过每日工作日报")
+		return "当前不在任何工作日的工作时间内，跳过每日工作日报", nil
+	}
+	start, end := bounds.Start, bounds.End
 
 	// 生成日报
 	summary, err := s.aiAnalyzer.AnalyzePeriod(start, end)
 	if err != nil {
 		fmt.Printf("❌ 生成每日工作日报失败: %v\n", err)
-		return
+		return "", err
 	}
 
 	fmt.Println("✅ 每日工作日报生成完成！")
@@ -335,99 +402,140 @@ func (s *Scheduler) runDailyReport() {
 	hours := totalMinutes / 60
 	minutes := totalMinutes % 60
 	fmt.Printf("⏱️  工作时长：%d小时%d分钟\n", hours, minutes)
+	return fmt.Sprintf("%s - %s 日报生成完成，工作时长 %d小时%d分钟", start.Format("15:04"), end.Format("15:04"), hours, minutes), nil
 }
 
-
-// addAutoStartCaptureJob 添加工作开始时间自动启动截图的任务
+// addAutoStartCaptureJob 添加工作时段开始时自动启动截图的任务（如上班、午休结束）
 func (s *Scheduler) addAutoStartCaptureJob() error {
-	schedule := s.configMgr.GetSchedule()
-
-	// 解析工作开始时间
-	startTime, err := time.Parse("15:04", schedule.StartTime)
-	if err != nil {
-		return fmt.Errorf("无效的开始时间格式: %w", err)
-	}
-
-	hour := startTime.Hour()
-	minute := startTime.Minute()
-
-	// 创建 cron 表达式，使用配置的工作日
-	// 例如：09:00 工作日1,2,3,4,5 -> "0 9 * * 1,2,3,4,5"
-	weekDays := workDaysToCron(schedule.WorkDays)
-	cronExpr := fmt.Sprintf("%d %d * * %s", minute, hour, weekDays)
-
-	_, err = s.cron.AddFunc(cronExpr, s.autoStartCapture)
+	times, err := s.addWindowJobs(JobAutoStartCapture, func(windows []workday.Range) []time.Time {
+		var starts []time.Time
+		for _, w := range windows {
+			starts = append(starts, w.Start)
+		}
+		return starts
+	})
 	if err != nil {
 		return fmt.Errorf("failed to add auto-start capture job: %w", err)
 	}
 
-	fmt.Printf("⏰ 工作时间自动启动截图任务已添加 (工作日 %02d:%02d 自动启动)\n", hour, minute)
+	fmt.Printf("⏰ 工作时间自动启动截图任务已添加 (工作日 %s 自动启动)\n", strings.Join(times, ", "))
 	return nil
 }
 
 // autoStartCapture 自动启动截图（在工作开始时间）
-func (s *Scheduler) autoStartCapture() {
+func (s *Scheduler) autoStartCapture() (string, error) {
 	fmt.Println("⏰ 到达工作开始时间，检查是否需要自动启动截图...")
 
 	// 检查截图引擎是否已经在运行
 	if s.captureEng.IsRunning() {
 		fmt.Println("ℹ️ 截图引擎已在运行中，无需启动")
-		return
+		return "截图引擎已在运行中，无需启动", nil
 	}
 
 	// 启动截图引擎
 	fmt.Println("🚀 自动启动截图引擎...")
 	if err := s.captureEng.Start(); err != nil {
 		fmt.Printf("❌ 自动启动截图引擎失败: %v\n", err)
-		return
+		return "", err
 	}
 
 	fmt.Println("✅ 截图引擎已自动启动")
+	return "截图引擎已自动启动", nil
 }
 
-// addAutoStopCaptureJob 添加工作结束时间自动停止截图的任务
+// addAutoStopCaptureJob 添加工作时段结束时自动停止截图的任务（如午休、下班）
 func (s *Scheduler) addAutoStopCaptureJob() error {
-	schedule := s.configMgr.GetSchedule()
-
-	// 解析工作结束时间
-	endTime, err := time.Parse("15:04", schedule.EndTime)
-	if err != nil {
-		return fmt.Errorf("无效的结束时间格式: %w", err)
-	}
-
-	hour := endTime.Hour()
-	minute := endTime.Minute()
-
-	// 创建 cron 表达式，使用配置的工作日
-	// 例如：18:00 工作日1,2,3,4,5 -> "0 18 * * 1,2,3,4,5"
-	weekDays := workDaysToCron(schedule.WorkDays)
-	cronExpr := fmt.Sprintf("%d %d * * %s", minute, hour, weekDays)
-
-	_, err = s.cron.AddFunc(cronExpr, s.autoStopCapture)
+	times, err := s.addWindowJobs(JobAutoStopCapture, func(windows []workday.Range) []time.Time {
+		var ends []time.Time
+		for _, w := range windows {
+			ends = append(ends, w.End)
+		}
+		return ends
+	})
 	if err != nil {
 		return fmt.Errorf("failed to add auto-stop capture job: %w", err)
 	}
 
-	fmt.Printf("⏰ 工作时间自动停止截图任务已添加 (工作日 %02d:%02d 自动停止)\n", hour, minute)
+	fmt.Printf("⏰ 工作时间自动停止截图任务已添加 (工作日 %s 自动停止)\n", strings.Join(times, ", "))
 	return nil
 }
 
+// addWindowJobs 按工作时段添加定时任务
+// pick 从某天的时段列表中选出触发时间点。由于节假日与调休会改变某天是否工作，
+// 任务在所有可能的时间点每天触发，执行前再按当天实际的工作时段判断是否需要执行。
+// 返回添加的时间点（"15:04"）。
+func (s *Scheduler) addWindowJobs(name string, pick func(windows []workday.Range) []time.Time) ([]string, error) {
+	schedule := s.configMgr.GetSchedule()
+
+	// 收集每个星期几（以 2024-01-07 周日开始的一周为参考）以及调休日可能用到的时间点
+	ref := time.Date(2024, 1, 7, 0, 0, 0, 0, time.Local)
+	candidates := [][]workday.Range{workday.RangesOn(ref, workday.DefaultWindows(schedule))}
+	for weekday := 0; weekday < 7; weekday++ {
+		candidates = append(candidates, workday.RangesOn(ref, workday.WindowsForWeekday(schedule, time.Weekday(weekday))))
+	}
+
+	seen := make(map[string]bool)
+	for _, windows := range candidates {
+		if len(windows) == 0 {
+			continue
+		}
+		for _, t := range pick(windows) {
+			seen[t.Format("15:04")] = true
+		}
+	}
+
+	times := make([]string, 0, len(seen))
+	for key := range seen {
+		times = append(times, key)
+	}
+	sort.Strings(times)
+
+	guard := func() bool {
+		now := clock.Now()
+		schedule := s.configMgr.GetSchedule()
+
+		// 同时检查前一逻辑日，跨午夜的时段（如夜班 06:00 下班）属于前一天
+		day := workday.LogicalDay(schedule, now)
+		for _, date := range []time.Time{day.AddDate(0, 0, -1), day} {
+			windows := workday.Windows(schedule, date)
+			if len(windows) == 0 {
+				continue
+			}
+			for _, t := range pick(windows) {
+				if t.Format("2006-01-02 15:04") == now.Format("2006-01-02 15:04") {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	for _, key := range times {
+		t, _ := time.Parse("15:04", key)
+		if err := s.scheduleJob(name, fmt.Sprintf("%d %d * * *", t.Minute(), t.Hour()), guard); err != nil {
+			return nil, err
+		}
+	}
+	return times, nil
+}
+
 // autoStopCapture 自动停止截图（在工作结束时间）
-func (s *Scheduler) autoStopCapture() {
+func (s *Scheduler) autoStopCapture() (string, error) {
 	fmt.Println("⏰ 到达工作结束时间，检查是否需要自动停止截图...")
 
 	// 检查截图引擎是否在运行
 	if !s.captureEng.IsRunning() {
 		fmt.Println("ℹ️ 截图引擎未运行，无需停止")
-		return
+		return "截图引擎未运行，无需停止", nil
 	}
 
 	// 停止截图引擎
 	fmt.Println("🛑 自动停止截图引擎...")
 	if err := s.captureEng.Stop(); err != nil {
 		fmt.Printf("❌ 自动停止截图引擎失败: %v\n", err)
-		return
+		return "", err
 	}
 
 	fmt.Println("✅ 截图引擎已自动停止")
+	return "截图引擎已自动停止", nil
 }
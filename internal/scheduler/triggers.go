@@ -0,0 +1,160 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"WorkTrackerAI/internal/ai"
+	"WorkTrackerAI/pkg/clock"
+	"WorkTrackerAI/pkg/imagediff"
+	"WorkTrackerAI/pkg/models"
+	"WorkTrackerAI/pkg/workday"
+)
+
+const (
+	triggerCheckInterval = 30 * time.Second // 事件触发条件检查周期
+	triggerRetryDelay    = 5 * time.Minute  // 事件触发分析失败后暂停触发的时长
+)
+
+// screenshotFingerprint 截图及其指纹
+type screenshotFingerprint struct {
+	id int64
+	fp imagediff.Fingerprint
+}
+
+// runEventTriggers 检查事件触发条件，满足任一条件时分析当前时间段中尚未分析的部分
+// 分析通过 AnalyzeSegment 占用当前时间段，与定时分析共用去重；
+// 分析失败不加入补分析队列，时间段结束后由定时分析补齐。
+func (s *Scheduler) runEventTriggers() (string, error) {
+	schedule := s.configMgr.GetSchedule()
+	triggers := schedule.Triggers
+	if triggers.ScreenshotCount <= 0 && triggers.IdleMinutes <= 0 && triggers.ActivityChange <= 0 {
+		return "", nil
+	}
+
+	now := clock.Now()
+	if now.Before(s.triggerRetryAt) {
+		return "", nil
+	}
+
+	seg := workday.NewSegmenter(schedule).Segment(now)
+
+	// 从当前时间段中已分析到的位置开始
+	from := seg.Start
+	covered, err := s.storageMgr.SummaryCoveredUntil(seg.Start, seg.End)
+	if err != nil {
+		return "", err
+	}
+	if covered.After(from) {
+		from = covered
+	}
+
+	// 待分析时间不足最小间隔时不触发，避免频繁调用 AI
+	if now.Sub(from) < time.Duration(triggers.MinInterval)*time.Minute || !from.Before(now) {
+		return "", nil
+	}
+	if schedule.Enabled && !workday.OverlapsWorkTime(schedule, from, now) {
+		return "", nil
+	}
+
+	screenshots, err := s.storageMgr.GetScreenshots(from, now)
+	if err != nil {
+		return "", err
+	}
+	if len(screenshots) == 0 {
+		return "", nil
+	}
+
+	reason := s.triggerReason(triggers, screenshots, now)
+	if reason == "" {
+		return "", nil
+	}
+
+	period := fmt.Sprintf("%s - %s", from.Format("15:04"), now.Format("15:04"))
+	fmt.Printf("⚡ 事件触发分析 (%s): %s...\n", reason, period)
+
+	summary, err := s.aiAnalyzer.AnalyzeSegment(seg.Start, now)
+	if errors.Is(err, ai.ErrAlreadyAnalyzed) || errors.Is(err, ai.ErrAnalysisRunning) {
+		return "", nil
+	}
+	if err != nil {
+		s.triggerRetryAt = now.Add(triggerRetryDelay)
+		fmt.Printf("❌ 事件触发分析失败，%s 后再尝试: %v\n", triggerRetryDelay, err)
+		return "", err
+	}
+
+	fmt.Printf("✅ 事件触发分析完成：%s，摘要：%s\n", period, summary.Summary)
+	return fmt.Sprintf("%s: %s 分析完成", reason, period), nil
+}
+
+// triggerReason 判断待分析截图是否满足触发条件，返回触发原因（不满足时返回空字符串）
+// 无变化的心跳记录不算新增截图，也不算屏幕活动
+func (s *Scheduler) triggerReason(triggers models.AnalysisTriggers, screenshots []*models.Screenshot, now time.Time) string {
+	changed := make([]*models.Screenshot, 0, len(screenshots))
+	for _, ss := range screenshots {
+		if !ss.Unchanged {
+			changed = append(changed, ss)
+		}
+	}
+
+	if n := triggers.ScreenshotCount; n > 0 && len(changed) >= n {
+		return fmt.Sprintf("新增 %d 张截图", len(changed))
+	}
+
+	// 全是心跳时画面至少从第一条心跳起就没有变化
+	lastActive := screenshots[0].Timestamp
+	if len(changed) > 0 {
+		lastActive = changed[len(changed)-1].Timestamp
+	}
+	if m := triggers.IdleMinutes; m > 0 && now.Sub(lastActive) >= time.Duration(m)*time.Minute {
+		return fmt.Sprintf("屏幕已 %d 分钟无活动", int(now.Sub(lastActive).Minutes()))
+	}
+
+	if threshold := triggers.ActivityChange; threshold > 0 && len(changed) > 0 {
+		if change, ok := s.activityChange(changed); ok && change >= threshold {
+			return fmt.Sprintf("画面变化 %.0f%%", change*100)
+		}
+	}
+	return ""
+}
+
+// activityChange 比较待分析部分第一张与最新一张截图（同一屏幕）的画面差异
+func (s *Scheduler) activityChange(screenshots []*models.Screenshot) (float64, bool) {
+	last := screenshots[len(screenshots)-1]
+	var first *models.Screenshot
+	for _, ss := range screenshots {
+		if ss.ScreenIndex == last.ScreenIndex {
+			first = ss
+			break
+		}
+	}
+	if first == nil || first.ID == last.ID {
+		return 0, false
+	}
+
+	base, err := s.baselineFingerprint(first)
+	if err != nil {
+		return 0, false
+	}
+	current, err := imagediff.Load(last.FilePath)
+	if err != nil {
+		return 0, false
+	}
+	return imagediff.Difference(base, current), true
+}
+
+// baselineFingerprint 获取待分析部分基准截图的指纹，基准不变时使用缓存避免重复解码
+// 只在事件触发任务中调用（同一任务不会并发执行），无需加锁
+func (s *Scheduler) baselineFingerprint(ss *models.Screenshot) (imagediff.Fingerprint, error) {
+	if s.baseline.id == ss.ID {
+		return s.baseline.fp, nil
+	}
+
+	fp, err := imagediff.Load(ss.FilePath)
+	if err != nil {
+		return fp, err
+	}
+	s.baseline.id, s.baseline.fp = ss.ID, fp
+	return fp, nil
+}
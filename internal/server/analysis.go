@@ -0,0 +1,84 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"WorkTrackerAI/internal/ai"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleGetAnalysisRuns 获取最近的分析记录
+func (s *Server) handleGetAnalysisRuns(c *gin.Context) {
+	limit := 50
+	if l := c.Query("limit"); l != "" {
+		fmt.Sscanf(l, "%d", &limit)
+	}
+
+	runs, err := s.storageMgr.GetRecentAnalysisRuns(limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, runs)
+}
+
+// handleGetAnalysisRun 获取单条分析记录（包含提示词与原始响应）
+func (s *Server) handleGetAnalysisRun(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的记录 ID"})
+		return
+	}
+
+	run, err := s.storageMgr.GetAnalysisRun(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, run)
+}
+
+// handleReplayAnalysisRun 重放分析记录
+// 请求体: {"mode": "parse"} 仅重新解析，{"mode": "resend"} 重新发送相同请求
+func (s *Server) handleReplayAnalysisRun(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的记录 ID"})
+		return
+	}
+
+	var req struct {
+		Mode string `json:"mode"`
+	}
+	_ = c.ShouldBindJSON(&req)
+	if req.Mode == "" {
+		req.Mode = ai.ReplayModeParse
+	}
+
+	result, err := s.aiAnalyzer.ReplayRun(id, req.Mode)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// handleGetAnalysisBacklog 获取等待补分析的时间段
+func (s *Server) handleGetAnalysisBacklog(c *gin.Context) {
+	items, err := s.storageMgr.GetPendingBacklog()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"pending": len(items),
+		"items":   items,
+	})
+}
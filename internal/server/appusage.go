@@ -0,0 +1,43 @@
+package server
+
+import (
+	"net/http"
+
+	"WorkTrackerAI/pkg/appusage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleGetAppUsage 获取时间范围内按前台窗口实测的应用与窗口标题使用时长
+// 优先使用窗口采样计算；采样已随截图清理时，使用分析时按时间段保存的统计
+func (s *Server) handleGetAppUsage(c *gin.Context) {
+	start, end, err := s.parseRangeQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	windows, err := s.storageMgr.GetScreenshotWindows(start, end)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	gaps, err := s.storageMgr.GetCaptureGaps(start, end)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	report := appusage.Compute(windows, gaps, start, end, appusage.MaxGap(s.configMgr.GetCapture()))
+	if report.Samples == 0 {
+		rows, err := s.storageMgr.GetSegmentAppUsage(start, end)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		report = appusage.FromRows(rows, start, end)
+	}
+
+	c.JSON(http.StatusOK, report)
+}
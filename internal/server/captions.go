@@ -0,0 +1,47 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleGetCaptions 获取时间范围内的逐张截图描述（用于时间轴定位具体时刻）
+func (s *Server) handleGetCaptions(c *gin.Context) {
+	start, end, err := s.parseRangeQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	captions, err := s.storageMgr.GetCaptions(start, end)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, captions)
+}
+
+// handleSearchCaptions 按关键词搜索截图描述
+func (s *Server) handleSearchCaptions(c *gin.Context) {
+	keyword := c.Query("q")
+	if keyword == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "搜索关键词不能为空"})
+		return
+	}
+
+	limit := 50
+	if l := c.Query("limit"); l != "" {
+		fmt.Sscanf(l, "%d", &limit)
+	}
+
+	captions, err := s.storageMgr.SearchCaptions(keyword, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, captions)
+}
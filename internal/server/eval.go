@@ -0,0 +1,112 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"WorkTrackerAI/pkg/logger"
+	"WorkTrackerAI/pkg/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// evalRequest 创建评测任务的请求体
+// periods 与 summary_ids 至少提供一个；summary_ids 会使用已保存总结的时间段
+type evalRequest struct {
+	Name       string                 `json:"name"`
+	Periods    []models.EvalPeriod    `json:"periods"`
+	SummaryIDs []int64                `json:"summary_ids"`
+	Candidates []models.EvalCandidate `json:"candidates"`
+}
+
+// handleCreateEval 创建并在后台执行评测任务
+func (s *Server) handleCreateEval(c *gin.Context) {
+	var req evalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	periods := req.Periods
+	for _, id := range req.SummaryIDs {
+		summary, err := s.storageMgr.GetWorkSummary(id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		periods = append(periods, models.EvalPeriod{Start: summary.StartTime, End: summary.EndTime})
+	}
+
+	run, err := s.aiAnalyzer.CreateEvaluation(req.Name, periods, req.Candidates)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 响应使用启动前的副本，后台执行会同时修改 run
+	resp := evalResponse(run)
+
+	// 评测可能耗时较长，在后台执行，通过 GET /api/eval/runs/:id 查看进度
+	go func() {
+		if err := s.aiAnalyzer.RunEvaluation(run); err != nil {
+			logger.Error("评测 %d 执行失败: %v", run.ID, err)
+		}
+	}()
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "评测任务已开始",
+		"run":     resp,
+	})
+}
+
+// handleGetEvalRuns 获取评测任务列表
+func (s *Server) handleGetEvalRuns(c *gin.Context) {
+	limit := 20
+	if l := c.Query("limit"); l != "" {
+		fmt.Sscanf(l, "%d", &limit)
+	}
+
+	runs, err := s.storageMgr.GetEvalRuns(limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp := make([]*models.EvalRun, len(runs))
+	for i, run := range runs {
+		resp[i] = evalResponse(run)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// handleGetEvalRun 获取评测任务及各候选的并列结果
+func (s *Server) handleGetEvalRun(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的评测 ID"})
+		return
+	}
+
+	run, err := s.storageMgr.GetEvalRun(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, evalResponse(run))
+}
+
+// evalResponse 复制评测任务用于响应，并去掉候选中的 API 密钥
+func evalResponse(run *models.EvalRun) *models.EvalRun {
+	resp := *run
+	resp.Periods = append([]models.EvalPeriod(nil), run.Periods...)
+	resp.Results = append([]*models.EvalResult(nil), run.Results...)
+	resp.Candidates = make([]models.EvalCandidate, len(run.Candidates))
+	for i, cand := range run.Candidates {
+		cand.APIKey = ""
+		resp.Candidates[i] = cand
+	}
+	return &resp
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"WorkTrackerAI/pkg/clock"
+	"WorkTrackerAI/pkg/models"
+	"WorkTrackerAI/pkg/workday"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleRateSummary 对工作总结评分（👍/👎），可附带修正后的总结
+// 请求体: {"rating": "up" | "down", "corrected_summary": "1.xxx;2.xxx;"}
+func (s *Server) handleRateSummary(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的总结 ID"})
+		return
+	}
+
+	var req struct {
+		Rating           string `json:"rating"`
+		CorrectedSummary string `json:"corrected_summary"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var rating int
+	switch req.Rating {
+	case "up":
+		rating = models.RatingUp
+	case "down":
+		rating = models.RatingDown
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "rating 必须为 up 或 down"})
+		return
+	}
+
+	if _, err := s.storageMgr.GetWorkSummary(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	now := clock.Now()
+	fb := &models.SummaryFeedback{
+		SummaryID:        id,
+		Rating:           rating,
+		CorrectedSummary: req.CorrectedSummary,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if err := s.storageMgr.SaveSummaryFeedback(fb); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, fb)
+}
+
+// handleGetFeedback 获取指定日期（默认今天）总结的评分
+func (s *Server) handleGetFeedback(c *gin.Context) {
+	start, end, err := s.parseRangeQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	feedback, err := s.storageMgr.GetFeedback(start, end)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, feedback)
+}
+
+// handleGetFeedbackTrends 按模型统计最近 N 天（默认 30 天）的评分趋势
+func (s *Server) handleGetFeedbackTrends(c *gin.Context) {
+	days := 30
+	if d := c.Query("days"); d != "" {
+		fmt.Sscanf(d, "%d", &days)
+	}
+
+	schedule := s.configMgr.GetSchedule()
+	since := workday.DayRange(schedule, workday.LogicalDay(schedule, clock.Now()).AddDate(0, 0, -days+1)).Start
+
+	trends, err := s.storageMgr.GetFeedbackTrends(since)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, trends)
+}
@@ -0,0 +1,26 @@
+package server
+
+import (
+	"net/http"
+
+	"WorkTrackerAI/pkg/capturegap"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleGetCaptureGaps 获取时间范围内的截屏暂停时段（如离开座位），用于在时间线中显示休息
+func (s *Server) handleGetCaptureGaps(c *gin.Context) {
+	start, end, err := s.parseRangeQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	gaps, err := s.storageMgr.GetCaptureGaps(start, end)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, capturegap.Report(gaps, start, end))
+}
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"WorkTrackerAI/pkg/clock"
+	"WorkTrackerAI/pkg/models"
+	"WorkTrackerAI/pkg/workday"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 单次导入的节假日文件大小上限
+const maxHolidayFileSize = 1 << 20
+
+// handleGetHolidays 获取节假日日历
+// 支持 ?year=2026 或 ?from=2026-01-01&to=2026-12-31，默认为今年
+func (s *Server) handleGetHolidays(c *gin.Context) {
+	year := clock.Now().Year()
+	if y := c.Query("year"); y != "" {
+		fmt.Sscanf(y, "%d", &year)
+	}
+	from, to := fmt.Sprintf("%d-01-01", year), fmt.Sprintf("%d-12-31", year)
+	if f := c.Query("from"); f != "" {
+		from = f
+	}
+	if t := c.Query("to"); t != "" {
+		to = t
+	}
+
+	holidays, err := s.storageMgr.GetHolidays(from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, holidays)
+}
+
+// handleAddHoliday 手动添加节假日或调休上班日
+// 请求体: {"date": "2026-10-01", "name": "国庆节", "kind": "holiday"}，kind 为 holiday 或 workday
+func (s *Server) handleAddHoliday(c *gin.Context) {
+	var req models.Holiday
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if _, err := time.Parse("2006-01-02", req.Date); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的日期格式，应为 2006-01-02"})
+		return
+	}
+	if req.Kind == "" {
+		req.Kind = models.HolidayKindOff
+	}
+	if req.Kind != models.HolidayKindOff && req.Kind != models.HolidayKindWorkday {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "kind 必须为 holiday 或 workday"})
+		return
+	}
+	req.Source = "manual"
+
+	if err := s.storageMgr.SaveHolidays([]*models.Holiday{&req}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, req)
+}
+
+// handleDeleteHoliday 删除某天的节假日设置（恢复按工作日配置判断）
+func (s *Server) handleDeleteHoliday(c *gin.Context) {
+	deleted, err := s.storageMgr.DeleteHoliday(c.Param("date"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "该日期不在节假日日历中"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "已删除"})
+}
+
+// handleImportHolidays 导入节假日日历文件（ICS 或 JSON）
+// 支持 multipart 上传（字段名 file）或直接以请求体发送文件内容
+func (s *Server) handleImportHolidays(c *gin.Context) {
+	var reader io.Reader = c.Request.Body
+	if file, err := c.FormFile("file"); err == nil {
+		f, err := file.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		defer f.Close()
+		reader = f
+	}
+
+	data, err := io.ReadAll(io.LimitReader(reader, maxHolidayFileSize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	holidays, err := workday.ParseHolidays(data)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(holidays) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "文件中没有节假日条目"})
+		return
+	}
+
+	if err := s.storageMgr.SaveHolidays(holidays); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  fmt.Sprintf("已导入 %d 天", len(holidays)),
+		"imported": len(holidays),
+	})
+}
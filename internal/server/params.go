@@ -0,0 +1,73 @@
+package server
+
+import (
+	"fmt"
+	"time"
+
+	"WorkTrackerAI/pkg/clock"
+	"WorkTrackerAI/pkg/workday"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 查询参数支持的时间格式
+var timeParamLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+}
+
+// parseTimeParam 解析时间参数（按本地时区）
+func parseTimeParam(value string) (time.Time, error) {
+	for _, layout := range timeParamLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("无效的时间格式: %s", value)
+}
+
+// parseRangeQuery 从查询参数中解析时间范围
+// 支持 ?start=...&end=... 或 ?date=2006-01-02（整个逻辑日），都未提供时默认为今天
+func (s *Server) parseRangeQuery(c *gin.Context) (time.Time, time.Time, error) {
+	if startStr, endStr := c.Query("start"), c.Query("end"); startStr != "" || endStr != "" {
+		start, err := parseTimeParam(startStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		end, err := parseTimeParam(endStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		if !end.After(start) {
+			return time.Time{}, time.Time{}, fmt.Errorf("结束时间必须晚于开始时间")
+		}
+		return start, end, nil
+	}
+
+	date, err := s.parseDateQuery(c)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	day := workday.DayRange(s.configMgr.GetSchedule(), date)
+	return day.Start, day.End, nil
+}
+
+// parseDateQuery 解析 ?date=2006-01-02，未提供时返回今天所属的逻辑日
+func (s *Server) parseDateQuery(c *gin.Context) (time.Time, error) {
+	d := c.Query("date")
+	if d == "" {
+		return workday.LogicalDay(s.configMgr.GetSchedule(), clock.Now()), nil
+	}
+	date, err := time.ParseInLocation("2006-01-02", d, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("无效的日期格式")
+	}
+	return date, nil
+}
+
+// today 返回今天所属逻辑日的时间范围
+func (s *Server) today() workday.Range {
+	return workday.Today(s.configMgr.GetSchedule(), clock.Now())
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handlePauseService 开启私密模式，duration 为时长（如 "15m"、"1h"）或 "tomorrow"（到下一个逻辑日）
+func (s *Server) handlePauseService(c *gin.Context) {
+	var req struct {
+		Duration string `json:"duration"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Duration == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "暂停时长不能为空"})
+		return
+	}
+
+	until, err := s.captureEng.PauseEnd(req.Duration)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	gap, err := s.captureEng.Pause(until)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "私密模式已开启，截屏暂停至 " + gap.EndTime.Format("01-02 15:04"),
+		"until":   gap.EndTime,
+	})
+}
+
+// handleResumeService 提前结束私密模式
+func (s *Server) handleResumeService(c *gin.Context) {
+	if !s.captureEng.Resume() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "当前未处于私密模式"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "私密模式已结束，恢复截屏"})
+}
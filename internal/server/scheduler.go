@@ -0,0 +1,92 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"WorkTrackerAI/internal/scheduler"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleGetSchedulerJobs 获取所有调度任务的状态（下次/上次执行时间、最近结果与错误）
+func (s *Server) handleGetSchedulerJobs(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"running": s.scheduler.IsRunning(),
+		"jobs":    s.scheduler.Jobs(),
+	})
+}
+
+// handleGetSchedulerRuns 获取调度任务执行历史
+// 支持 ?job=analysis 按任务过滤，?limit=50 限制条数
+func (s *Server) handleGetSchedulerRuns(c *gin.Context) {
+	limit := 50
+	if l := c.Query("limit"); l != "" {
+		fmt.Sscanf(l, "%d", &limit)
+	}
+
+	runs, err := s.storageMgr.GetJobRuns(c.Query("job"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, runs)
+}
+
+// handleTriggerSchedulerJob 立即执行任务（在后台运行，忽略暂停状态）
+func (s *Server) handleTriggerSchedulerJob(c *gin.Context) {
+	name := c.Param("name")
+	if err := s.scheduler.TriggerJob(name); err != nil {
+		c.JSON(schedulerErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "任务已触发", "job": name})
+}
+
+// handlePauseSchedulerJob 暂停任务的定时执行
+func (s *Server) handlePauseSchedulerJob(c *gin.Context) {
+	s.setSchedulerJobPaused(c, true)
+}
+
+// handleResumeSchedulerJob 恢复任务的定时执行
+func (s *Server) handleResumeSchedulerJob(c *gin.Context) {
+	s.setSchedulerJobPaused(c, false)
+}
+
+// setSchedulerJobPaused 暂停或恢复任务，返回任务最新状态
+func (s *Server) setSchedulerJobPaused(c *gin.Context, paused bool) {
+	name := c.Param("name")
+
+	var err error
+	if paused {
+		err = s.scheduler.PauseJob(name)
+	} else {
+		err = s.scheduler.ResumeJob(name)
+	}
+	if err != nil {
+		c.JSON(schedulerErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	job, err := s.scheduler.Job(name)
+	if err != nil {
+		c.JSON(schedulerErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// schedulerErrorStatus 调度器错误对应的 HTTP 状态码
+func schedulerErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, scheduler.ErrJobNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, scheduler.ErrJobRunning):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -2,6 +2,8 @@ package server
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -13,8 +15,12 @@ import (
 	"WorkTrackerAI/internal/ai"
 	"WorkTrackerAI/internal/capture"
 	"WorkTrackerAI/internal/config"
+	"WorkTrackerAI/internal/scheduler"
 	"WorkTrackerAI/internal/storage"
+	"WorkTrackerAI/pkg/clock"
 	"WorkTrackerAI/pkg/models"
+	"WorkTrackerAI/pkg/privacy"
+	"WorkTrackerAI/pkg/workday"
 
 	"github.com/gin-gonic/gin"
 )
@@ -26,6 +32,7 @@ type Server struct {
 	storageMgr  *storage.Manager
 	captureEng  *capture.Engine
 	aiAnalyzer  *ai.Analyzer
+	scheduler   *scheduler.Scheduler
 	addr        string
 	version     string
 	httpServer  *http.Server
@@ -37,6 +44,7 @@ func NewServer(
 	storageMgr *storage.Manager,
 	captureEng *capture.Engine,
 	aiAnalyzer *ai.Analyzer,
+	sched *scheduler.Scheduler,
 	version string,
 ) *Server {
 	gin.SetMode(gin.ReleaseMode)
@@ -51,6 +59,7 @@ func NewServer(
 		storageMgr: storageMgr,
 		captureEng: captureEng,
 		aiAnalyzer: aiAnalyzer,
+		scheduler:  sched,
 		addr:       addr,
 		version:    version,
 	}
@@ -83,25 +92,62 @@ func (s *Server) setupRoutes() {
 		// AI 相关
 		api.POST("/ai/test-connection", s.handleTestAIConnection)
 
+		// 分析记录（审计与重放）
+		api.GET("/analysis/runs", s.handleGetAnalysisRuns)
+		api.GET("/analysis/runs/:id", s.handleGetAnalysisRun)
+		api.POST("/analysis/runs/:id/replay", s.handleReplayAnalysisRun)
+		api.GET("/analysis/backlog", s.handleGetAnalysisBacklog)
+
+		// 提示词与模型评测
+		api.GET("/eval/runs", s.handleGetEvalRuns)
+		api.POST("/eval/runs", s.handleCreateEval)
+		api.GET("/eval/runs/:id", s.handleGetEvalRun)
+
 		// 截图管理
 		api.GET("/screenshots", s.handleGetScreenshots)
 		api.GET("/screenshots/:id", s.handleGetScreenshot)
 		api.DELETE("/screenshots/:id", s.handleDeleteScreenshot)
 		api.POST("/screenshots/capture", s.handleCaptureNow)
 
+		// 截图描述
+		api.GET("/captions", s.handleGetCaptions)
+		api.GET("/captions/search", s.handleSearchCaptions)
+
 		// 工作总结
 		api.GET("/summaries", s.handleGetSummaries)
 		api.GET("/summaries/:date", s.handleGetSummariesByDate)
 		api.POST("/summaries/analyze", s.handleAnalyzeNow)
+		api.POST("/summaries/:id/feedback", s.handleRateSummary)
+
+		// 总结评分
+		api.GET("/feedback", s.handleGetFeedback)
+		api.GET("/feedback/trends", s.handleGetFeedbackTrends)
 
 		// 统计数据
 		api.GET("/stats/today", s.handleGetTodayStats)
 		api.GET("/stats/storage", s.handleGetStorageStats)
 		api.POST("/stats/open-folder", s.handleOpenStorageFolder)
+		api.GET("/stats/app-usage", s.handleGetAppUsage)
+		api.GET("/stats/gaps", s.handleGetCaptureGaps)
+
+		// 节假日日历
+		api.GET("/holidays", s.handleGetHolidays)
+		api.POST("/holidays", s.handleAddHoliday)
+		api.POST("/holidays/import", s.handleImportHolidays)
+		api.DELETE("/holidays/:date", s.handleDeleteHoliday)
+
+		// 调度任务
+		api.GET("/scheduler/jobs", s.handleGetSchedulerJobs)
+		api.GET("/scheduler/runs", s.handleGetSchedulerRuns)
+		api.POST("/scheduler/jobs/:name/trigger", s.handleTriggerSchedulerJob)
+		api.POST("/scheduler/jobs/:name/pause", s.handlePauseSchedulerJob)
+		api.POST("/scheduler/jobs/:name/resume", s.handleResumeSchedulerJob)
 
 		// 服务控制
 		api.POST("/service/start", s.handleStartService)
 		api.POST("/service/stop", s.handleStopService)
+		api.POST("/service/pause", s.handlePauseService)
+		api.POST("/service/resume", s.handleResumeService)
 		api.GET("/service/status", s.handleGetStatus)
 	}
 }
@@ -175,20 +221,68 @@ func (s *Server) handleGetConfig(c *gin.Context) {
 
 // handleUpdateConfig 更新配置
 func (s *Server) handleUpdateConfig(c *gin.Context) {
-	var newConfig models.AppConfig
-	if err := c.ShouldBindJSON(&newConfig); err != nil {
+	body, err := c.GetRawData()
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if err := s.configMgr.Update(func(cfg *models.AppConfig) {
-		*cfg = newConfig
-	}); err != nil {
+	// 以当前配置为基础合并请求体，请求中未包含的字段保持不变
+	newConfig := s.configMgr.Get()
+	if err := mergeConfig(newConfig, body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 校验工作时段与分析时间段配置
+	if err := workday.ValidateSchedule(newConfig.Schedule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 校验截屏配置
+	if err := capture.ValidateConfig(newConfig.Capture); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 校验隐私规则
+	if err := privacy.Validate(newConfig.Privacy); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 保存后通知调度器与截屏引擎立即应用
+	result, err := s.configMgr.UpdateAndApply(func(cfg *models.AppConfig) {
+		*cfg = *newConfig
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "配置已更新"})
+	c.JSON(http.StatusOK, gin.H{
+		"message":          "配置已更新",
+		"applied":          result.Applied,
+		"restart_required": result.RestartRequired,
+	})
+}
+
+// mergeConfig 将请求体合并到 cfg
+// encoding/json 解析到已有的 map 时只会新增或覆盖条目，请求中包含的 map 字段先清空，整体替换
+func mergeConfig(cfg *models.AppConfig, body []byte) error {
+	var present struct {
+		Schedule struct {
+			WeekdayWindows json.RawMessage `json:"weekday_windows"`
+		} `json:"schedule"`
+	}
+	if err := json.Unmarshal(body, &present); err != nil {
+		return err
+	}
+	if present.Schedule.WeekdayWindows != nil {
+		cfg.Schedule.WeekdayWindows = nil
+	}
+	return json.Unmarshal(body, cfg)
 }
 
 // handleGetScreens 获取屏幕列表
@@ -247,16 +341,15 @@ func (s *Server) handleCaptureNow(c *gin.Context) {
 
 // handleGetSummaries 获取工作总结列表
 func (s *Server) handleGetSummaries(c *gin.Context) {
-	// 默认获取今天的
-	date := time.Now()
-	if d := c.Query("date"); d != "" {
-		parsed, err := time.Parse("2006-01-02", d)
-		if err == nil {
-			date = parsed
-		}
+	// 默认获取今天的（按逻辑日）
+	date, err := s.parseDateQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	summaries, err := s.storageMgr.GetWorkSummaries(date)
+	day := workday.DayRange(s.configMgr.GetSchedule(), date)
+	summaries, err := s.storageMgr.GetWorkSummaries(day.Start, day.End)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -268,13 +361,14 @@ func (s *Server) handleGetSummaries(c *gin.Context) {
 // handleGetSummariesByDate 获取指定日期的总结
 func (s *Server) handleGetSummariesByDate(c *gin.Context) {
 	dateStr := c.Param("date")
-	date, err := time.Parse("2006-01-02", dateStr)
+	date, err := time.ParseInLocation("2006-01-02", dateStr, time.Local)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的日期格式"})
 		return
 	}
 
-	summaries, err := s.storageMgr.GetWorkSummaries(date)
+	day := workday.DayRange(s.configMgr.GetSchedule(), date)
+	summaries, err := s.storageMgr.GetWorkSummaries(day.Start, day.End)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -283,12 +377,12 @@ func (s *Server) handleGetSummariesByDate(c *gin.Context) {
 	c.JSON(http.StatusOK, summaries)
 }
 
-// handleAnalyzeNow 立即触发 AI 分析（按整点分段，空段留空）
+// handleAnalyzeNow 立即触发 AI 分析（按配置的时间段切分，空段留空）
 // 行为：
 //   1. 获取当天截图的最早和最晚时间；
-//   2. 第一段：从最早截图时间 -> 下一个整点；
-//   3. 中间段：整点 -> 整点；
-//   4. 最后一段：整点 -> 最后截图时间（或当前时间）；
+//   2. 第一段：从最早截图时间 -> 下一个时间段边界；
+//   3. 中间段：时间段边界 -> 时间段边界；
+//   4. 最后一段：时间段边界 -> 最后截图时间；
 //   5. 如果某段没有截图，则不调用 AI，直接写入空占位。
 func (s *Server) handleAnalyzeNow(c *gin.Context) {
 	var req struct {
@@ -297,9 +391,9 @@ func (s *Server) handleAnalyzeNow(c *gin.Context) {
 	}
 	_ = c.ShouldBindJSON(&req)
 
-	// 1. 获取当天截图
-	now := time.Now()
-	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	// 1. 获取当天（逻辑日）截图
+	now := clock.Now()
+	startOfDay := s.today().Start
 
 	screenshots, err := s.storageMgr.GetScreenshots(startOfDay, now)
 	if err != nil {
@@ -314,33 +408,23 @@ func (s *Server) handleAnalyzeNow(c *gin.Context) {
 	firstTs := screenshots[0].Timestamp
 	lastTs := screenshots[len(screenshots)-1].Timestamp
 
-	// 2. 计算整点边界的时间段
+	// 2. 按配置的时长与对齐方式切分时间段（首段从最早截图开始，末段到最后截图结束）
+	ranges := workday.NewSegmenter(s.configMgr.GetSchedule()).Split(firstTs, lastTs)
+	if len(ranges) == 0 {
+		// 只有一张截图时，整个数据只有一段
+		ranges = []workday.Range{{Start: firstTs, End: lastTs}}
+	}
+
 	segments := []struct {
 		Start, End time.Time
 		HasData    bool
 	}{}
-
-	// 计算第一个整点边界（向上取整到下一个整点）
-	firstHourEnd := time.Date(
-		firstTs.Year(), firstTs.Month(), firstTs.Day(),
-		firstTs.Hour()+1, 0, 0, 0, firstTs.Location(),
-	)
-
-	// 第一段：从第一张截图到下一个整点
-	currentStart := firstTs
-	currentEnd := firstHourEnd
-
-	// 如果 lastTs 在第一个整点之前，整个数据只有一段
-	if lastTs.Before(firstHourEnd) || lastTs.Equal(firstHourEnd) {
-		currentEnd = lastTs
-	}
-
-	for {
-		// 检查该段是否有截图
+	for i, r := range ranges {
+		// 检查该段是否有截图（最后一段包含最后一张截图）
 		hasData := false
 		for _, ss := range screenshots {
-			if (ss.Timestamp.Equal(currentStart) || ss.Timestamp.After(currentStart)) &&
-				ss.Timestamp.Before(currentEnd) {
+			if !ss.Timestamp.Before(r.Start) &&
+				(ss.Timestamp.Before(r.End) || (i == len(ranges)-1 && ss.Timestamp.Equal(r.End))) {
 				hasData = true
 				break
 			}
@@ -350,29 +434,26 @@ func (s *Server) handleAnalyzeNow(c *gin.Context) {
 			Start, End time.Time
 			HasData    bool
 		}{
-			Start:   currentStart,
-			End:     currentEnd,
+			Start:   r.Start,
+			End:     r.End,
 			HasData: hasData,
 		})
+	}
 
-		// 如果已达到或超过最后截图时间，结束
-		if currentEnd.Equal(lastTs) || currentEnd.After(lastTs) {
-			break
-		}
-
-		// 下一段：从当前结束时间（整点）开始
-		currentStart = currentEnd
-		// 下一个结束时间：下一个整点
-		currentEnd = currentStart.Add(1 * time.Hour)
-
-		// 如果下一个整点超过 lastTs，则用 lastTs 作为结束
-		if currentEnd.After(lastTs) {
-			currentEnd = lastTs
-		}
+	// 3. 占用当天已过去的时间段，避免与定时分析、补分析同时分析同一时间段
+	release, err := s.aiAnalyzer.ClaimSegment(startOfDay, now)
+	if errors.Is(err, ai.ErrAnalysisRunning) {
+		c.JSON(http.StatusConflict, gin.H{"error": "已有分析任务正在运行，请稍后再试", "code": "already_running"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
+	defer release()
 
-	// 3. 清空当天已有的总结
-	if err := s.storageMgr.DeleteWorkSummariesForDate(now); err != nil {
+	// 清空当天已有的总结
+	if err := s.storageMgr.DeleteWorkSummaries(startOfDay, s.today().End); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("清空今日工作总结失败: %v", err)})
 		return
 	}
@@ -388,7 +469,7 @@ func (s *Server) handleAnalyzeNow(c *gin.Context) {
 				Summary:    "暂无截屏内容",
 				Activities: []models.Activity{},
 				AppUsage:   map[string]int{},
-				CreatedAt:  time.Now(),
+				CreatedAt:  clock.Now(),
 			}
 			if err := s.storageMgr.SaveWorkSummary(emptySummary); err != nil {
 				c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("保存空占位失败: %v", err)})
@@ -414,7 +495,8 @@ func (s *Server) handleAnalyzeNow(c *gin.Context) {
 
 // handleGetTodayStats 获取今日统计
 func (s *Server) handleGetTodayStats(c *gin.Context) {
-	screenshots, summaries, err := s.storageMgr.GetTodayStats()
+	today := s.today()
+	screenshots, summaries, err := s.storageMgr.GetDayStats(today.Start, today.End)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -455,7 +537,7 @@ func (s *Server) handleOpenStorageFolder(c *gin.Context) {
 
 	if target == "today" {
 		// 强制打开今日目录
-		today := time.Now().Format("2006-01-02")
+		today := clock.Now().Format("2006-01-02")
 		targetDir = filepath.Join(absPath, today)
 	} else {
 		// 打开截图根目录（data/screenshots）
@@ -527,14 +609,22 @@ func (s *Server) handleStopService(c *gin.Context) {
 
 // handleGetStatus 获取服务状态
 func (s *Server) handleGetStatus(c *gin.Context) {
-	screenshots, summaries, _ := s.storageMgr.GetTodayStats()
+	today := s.today()
+	screenshots, summaries, _ := s.storageMgr.GetDayStats(today.Start, today.End)
+	backlog, _ := s.storageMgr.CountPendingBacklog()
+	privateUntil := s.captureEng.PrivateUntil()
 
 	status := models.ServiceStatus{
-		Running:        s.captureEng.IsRunning(),
-		CaptureEnabled: s.configMgr.GetCapture().Enabled,
-		LastCapture:    s.captureEng.GetLastCapture(),
-		TodayCaptures:  screenshots,
-		TodaySummaries: summaries,
+		Running:         s.captureEng.IsRunning(),
+		CaptureEnabled:  s.configMgr.GetCapture().Enabled,
+		CaptureInterval: s.captureEng.GetEffectiveInterval().Seconds(),
+		Idle:            s.captureEng.IsIdle(),
+		Private:         !privateUntil.IsZero(),
+		PrivateUntil:    privateUntil,
+		LastCapture:     s.captureEng.GetLastCapture(),
+		TodayCaptures:   screenshots,
+		TodaySummaries:  summaries,
+		AnalysisBacklog: backlog,
 	}
 
 	c.JSON(http.StatusOK, status)
@@ -0,0 +1,47 @@
+//go:build !windows
+// +build !windows
+
+package singleton
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// Mutex 持有加锁的锁文件
+type Mutex struct {
+	file *os.File
+}
+
+// Close 释放文件锁
+func (m *Mutex) Close() error {
+	if m.file == nil {
+		return nil
+	}
+	syscall.Flock(int(m.file.Fd()), syscall.LOCK_UN)
+	return m.file.Close()
+}
+
+// EnsureSingleInstance 确保只有一个实例运行
+// 通过对数据目录下的锁文件加排他锁实现，进程退出（包括异常退出）时系统自动释放
+// 返回: 互斥锁对象（需要在程序退出时调用 Close）
+func EnsureSingleInstance(appName, dataDir string) (*Mutex, error) {
+	path := filepath.Join(dataDir, appName+".lock")
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("创建锁文件失败: %w", err)
+	}
+
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		file.Close()
+		if err == syscall.EWOULDBLOCK {
+			fmt.Printf("⚠️ %s 已经在运行中（锁文件: %s）\n", appName, path)
+			return nil, fmt.Errorf("应用已在运行")
+		}
+		return nil, fmt.Errorf("锁定锁文件失败: %w", err)
+	}
+
+	return &Mutex{file: file}, nil
+}
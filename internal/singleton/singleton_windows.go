@@ -67,8 +67,9 @@ func ShowMessageBox(title, message string) {
 
 // EnsureSingleInstance 确保只有一个实例运行
 // appName: 应用名称，用于互斥锁名称和提示消息
+// dataDir: 非 Windows 平台存放锁文件的目录，Windows 使用命名互斥锁，不使用该参数
 // 返回: 互斥锁对象（需要在程序退出时调用 Close）
-func EnsureSingleInstance(appName string) (*Mutex, error) {
+func EnsureSingleInstance(appName, dataDir string) (*Mutex, error) {
 	mutexName := fmt.Sprintf("Global\\%s_SingleInstance", appName)
 
 	mutex, isFirst, err := CreateMutex(mutexName)
@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"WorkTrackerAI/pkg/models"
+)
+
+const analysisRunColumns = `
+	id, start_time, end_time, provider, model,
+	COALESCE(max_tokens, 0), COALESCE(temperature, 0), prompt,
+	COALESCE(screenshot_ids_json, ''), COALESCE(raw_response, ''), parse_status,
+	COALESCE(error, ''), COALESCE(summary_id, 0), COALESCE(replay_of, 0),
+	COALESCE(latency_ms, 0), created_at
+`
+
+// SaveAnalysisRun 保存一次 AI 分析记录
+func (m *Manager) SaveAnalysisRun(run *models.AnalysisRun) error {
+	idsJSON, err := json.Marshal(run.ScreenshotIDs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal screenshot ids: %w", err)
+	}
+
+	query := `
+		INSERT INTO analysis_runs (
+			start_time, end_time, provider, model, max_tokens, temperature, prompt,
+			screenshot_ids_json, raw_response, parse_status, error, summary_id, replay_of,
+			latency_ms, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := m.db.Exec(query,
+		run.StartTime,
+		run.EndTime,
+		run.Provider,
+		run.Model,
+		run.MaxTokens,
+		run.Temperature,
+		run.Prompt,
+		string(idsJSON),
+		run.RawResponse,
+		run.ParseStatus,
+		run.Error,
+		run.SummaryID,
+		run.ReplayOf,
+		run.LatencyMs,
+		run.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert analysis run: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get insert id: %w", err)
+	}
+
+	run.ID = id
+	return nil
+}
+
+// GetAnalysisRun 按 ID 获取分析记录
+func (m *Manager) GetAnalysisRun(id int64) (*models.AnalysisRun, error) {
+	query := `SELECT ` + analysisRunColumns + ` FROM analysis_runs WHERE id = ?`
+
+	run, err := scanAnalysisRun(m.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("analysis run %d not found", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// GetRecentAnalysisRuns 获取最近的 N 条分析记录
+func (m *Manager) GetRecentAnalysisRuns(limit int) ([]*models.AnalysisRun, error) {
+	query := `SELECT ` + analysisRunColumns + ` FROM analysis_runs ORDER BY id DESC LIMIT ?`
+
+	rows, err := m.db.Query(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query analysis runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []*models.AnalysisRun
+	for rows.Next() {
+		run, err := scanAnalysisRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+
+	return runs, rows.Err()
+}
+
+// rowScanner 兼容 *sql.Row 与 *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanAnalysisRun 读取一条分析记录
+func scanAnalysisRun(row rowScanner) (*models.AnalysisRun, error) {
+	run := &models.AnalysisRun{}
+	var idsJSON string
+
+	err := row.Scan(
+		&run.ID,
+		&run.StartTime,
+		&run.EndTime,
+		&run.Provider,
+		&run.Model,
+		&run.MaxTokens,
+		&run.Temperature,
+		&run.Prompt,
+		&idsJSON,
+		&run.RawResponse,
+		&run.ParseStatus,
+		&run.Error,
+		&run.SummaryID,
+		&run.ReplayOf,
+		&run.LatencyMs,
+		&run.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan analysis run: %w", err)
+	}
+
+	if idsJSON != "" {
+		if err := json.Unmarshal([]byte(idsJSON), &run.ScreenshotIDs); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal screenshot ids: %w", err)
+		}
+	}
+
+	return run, nil
+}
@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"WorkTrackerAI/pkg/clock"
+	"WorkTrackerAI/pkg/models"
+)
+
+const backlogColumns = `
+	id, start_time, end_time, status, COALESCE(reason, ''), attempts,
+	COALESCE(last_error, ''), next_attempt_at, created_at, updated_at
+`
+
+// EnqueueBacklog 将时间段加入待补分析队列
+// 同一时间段已在队列中时更新原因与错误信息，并重新进入等待状态、重置重试次数
+func (m *Manager) EnqueueBacklog(start, end time.Time, reason, lastError string, nextAttempt time.Time) error {
+	now := clock.Now()
+	_, err := m.db.Exec(`
+		INSERT INTO analysis_backlog (start_time, end_time, status, reason, attempts, last_error, next_attempt_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, 0, ?, ?, ?, ?)
+		ON CONFLICT(start_time, end_time) DO UPDATE SET
+			status = excluded.status,
+			reason = excluded.reason,
+			attempts = 0,
+			last_error = excluded.last_error,
+			next_attempt_at = MIN(analysis_backlog.next_attempt_at, excluded.next_attempt_at),
+			updated_at = excluded.updated_at
+	`, start, end, models.BacklogStatusPending, reason, lastError, nextAttempt, now, now)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue backlog: %w", err)
+	}
+	return nil
+}
+
+// GetDueBacklog 获取已到重试时间的待补分析时间段（按时间先后）
+func (m *Manager) GetDueBacklog(now time.Time, limit int) ([]*models.BacklogItem, error) {
+	return m.queryBacklog(`
+		WHERE status = ? AND next_attempt_at <= ?
+		ORDER BY start_time ASC
+		LIMIT ?
+	`, models.BacklogStatusPending, now, limit)
+}
+
+// GetPendingBacklog 获取所有等待中的待补分析时间段
+func (m *Manager) GetPendingBacklog() ([]*models.BacklogItem, error) {
+	return m.queryBacklog(`
+		WHERE status = ?
+		ORDER BY start_time ASC
+	`, models.BacklogStatusPending)
+}
+
+// CountPendingBacklog 统计等待补分析的时间段数量
+func (m *Manager) CountPendingBacklog() (int, error) {
+	var count int
+	err := m.db.QueryRow(`SELECT COUNT(*) FROM analysis_backlog WHERE status = ?`, models.BacklogStatusPending).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count backlog: %w", err)
+	}
+	return count, nil
+}
+
+// IsBacklogged 判断时间段是否已在补分析队列中（任意状态）
+func (m *Manager) IsBacklogged(start, end time.Time) (bool, error) {
+	var count int
+	err := m.db.QueryRow(
+		`SELECT COUNT(*) FROM analysis_backlog WHERE start_time = ? AND end_time = ?`,
+		start,
+		end,
+	).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to query backlog: %w", err)
+	}
+	return count > 0, nil
+}
+
+// UpdateBacklog 更新待补分析时间段的状态、重试次数与下次重试时间
+func (m *Manager) UpdateBacklog(item *models.BacklogItem) error {
+	item.UpdatedAt = clock.Now()
+	_, err := m.db.Exec(`
+		UPDATE analysis_backlog
+		SET status = ?, attempts = ?, last_error = ?, next_attempt_at = ?, updated_at = ?
+		WHERE id = ?
+	`, item.Status, item.Attempts, item.LastError, item.NextAttemptAt, item.UpdatedAt, item.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update backlog: %w", err)
+	}
+	return nil
+}
+
+// queryBacklog 查询待补分析队列
+func (m *Manager) queryBacklog(where string, args ...interface{}) ([]*models.BacklogItem, error) {
+	rows, err := m.db.Query(`SELECT `+backlogColumns+` FROM analysis_backlog `+where, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query backlog: %w", err)
+	}
+	defer rows.Close()
+
+	var items []*models.BacklogItem
+	for rows.Next() {
+		item := &models.BacklogItem{}
+		err := rows.Scan(
+			&item.ID,
+			&item.StartTime,
+			&item.EndTime,
+			&item.Status,
+			&item.Reason,
+			&item.Attempts,
+			&item.LastError,
+			&item.NextAttemptAt,
+			&item.CreatedAt,
+			&item.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan backlog: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"WorkTrackerAI/pkg/models"
+)
+
+// SaveScreenshotCaptions 批量保存截图描述（同一截图的旧描述会被覆盖）
+func (m *Manager) SaveScreenshotCaptions(captions []*models.ScreenshotCaption) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO screenshot_captions (screenshot_id, caption, provider, model, analysis_run_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(screenshot_id) DO UPDATE SET
+			caption = excluded.caption,
+			provider = excluded.provider,
+			model = excluded.model,
+			analysis_run_id = excluded.analysis_run_id,
+			created_at = excluded.created_at
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare caption insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, c := range captions {
+		if _, err := stmt.Exec(c.ScreenshotID, c.Caption, c.Provider, c.Model, c.AnalysisRunID, c.CreatedAt); err != nil {
+			return fmt.Errorf("failed to insert caption: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetCaptions 获取指定时间范围内的截图描述（按截图时间排序）
+func (m *Manager) GetCaptions(start, end time.Time) ([]*models.ScreenshotCaption, error) {
+	return m.queryCaptions(`
+		WHERE s.timestamp >= ? AND s.timestamp < ?
+		ORDER BY s.timestamp ASC
+	`, start, end)
+}
+
+// SearchCaptions 按关键词搜索截图描述（按时间倒序）
+func (m *Manager) SearchCaptions(keyword string, limit int) ([]*models.ScreenshotCaption, error) {
+	// 转义 LIKE 通配符，按字面匹配关键词
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(keyword)
+	return m.queryCaptions(`
+		WHERE c.caption LIKE ? ESCAPE '\'
+		ORDER BY s.timestamp DESC
+		LIMIT ?
+	`, "%"+escaped+"%", limit)
+}
+
+// queryCaptions 查询截图描述并关联截图时间
+func (m *Manager) queryCaptions(where string, args ...interface{}) ([]*models.ScreenshotCaption, error) {
+	query := `
+		SELECT c.id, c.screenshot_id, s.timestamp, c.caption,
+			COALESCE(c.provider, ''), COALESCE(c.model, ''), COALESCE(c.analysis_run_id, 0), c.created_at
+		FROM screenshot_captions c
+		JOIN screenshots s ON s.id = c.screenshot_id
+	` + where
+
+	rows, err := m.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query captions: %w", err)
+	}
+	defer rows.Close()
+
+	var captions []*models.ScreenshotCaption
+	for rows.Next() {
+		c := &models.ScreenshotCaption{}
+		err := rows.Scan(
+			&c.ID,
+			&c.ScreenshotID,
+			&c.Timestamp,
+			&c.Caption,
+			&c.Provider,
+			&c.Model,
+			&c.AnalysisRunID,
+			&c.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan caption: %w", err)
+		}
+		captions = append(captions, c)
+	}
+
+	return captions, rows.Err()
+}
@@ -0,0 +1,234 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"WorkTrackerAI/pkg/models"
+)
+
+// CreateEvalRun 创建评测任务记录
+// 候选中的 API 密钥不会写入数据库
+func (m *Manager) CreateEvalRun(run *models.EvalRun) error {
+	periodsJSON, err := json.Marshal(run.Periods)
+	if err != nil {
+		return fmt.Errorf("failed to marshal periods: %w", err)
+	}
+
+	candidates := make([]models.EvalCandidate, len(run.Candidates))
+	for i, cand := range run.Candidates {
+		cand.APIKey = ""
+		candidates[i] = cand
+	}
+	candidatesJSON, err := json.Marshal(candidates)
+	if err != nil {
+		return fmt.Errorf("failed to marshal candidates: %w", err)
+	}
+
+	result, err := m.db.Exec(
+		`INSERT INTO eval_runs (name, periods_json, candidates_json, status, error, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		run.Name,
+		string(periodsJSON),
+		string(candidatesJSON),
+		run.Status,
+		run.Error,
+		run.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert eval run: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get insert id: %w", err)
+	}
+
+	run.ID = id
+	return nil
+}
+
+// FinishEvalRun 更新评测任务的最终状态
+func (m *Manager) FinishEvalRun(id int64, status, errMsg string, finishedAt time.Time) error {
+	_, err := m.db.Exec(
+		`UPDATE eval_runs SET status = ?, error = ?, finished_at = ? WHERE id = ?`,
+		status, errMsg, finishedAt, id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update eval run: %w", err)
+	}
+	return nil
+}
+
+// SaveEvalResult 保存单条评测结果
+func (m *Manager) SaveEvalResult(res *models.EvalResult) error {
+	idsJSON, err := json.Marshal(res.ScreenshotIDs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal screenshot ids: %w", err)
+	}
+
+	query := `
+		INSERT INTO eval_results (
+			eval_run_id, period_start, period_end, candidate, provider, model, prompt,
+			screenshot_ids_json, raw_response, summary, json_valid, duration_plausible,
+			total_minutes, latency_ms, error, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := m.db.Exec(query,
+		res.EvalRunID,
+		res.PeriodStart,
+		res.PeriodEnd,
+		res.Candidate,
+		res.Provider,
+		res.Model,
+		res.Prompt,
+		string(idsJSON),
+		res.RawResponse,
+		res.Summary,
+		res.JSONValid,
+		res.DurationPlausible,
+		res.TotalMinutes,
+		res.LatencyMs,
+		res.Error,
+		res.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert eval result: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get insert id: %w", err)
+	}
+
+	res.ID = id
+	return nil
+}
+
+// GetEvalRuns 获取最近的评测任务（不含结果）
+func (m *Manager) GetEvalRuns(limit int) ([]*models.EvalRun, error) {
+	rows, err := m.db.Query(`
+		SELECT id, name, periods_json, candidates_json, status, COALESCE(error, ''), created_at, finished_at
+		FROM eval_runs
+		ORDER BY id DESC
+		LIMIT ?
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query eval runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []*models.EvalRun
+	for rows.Next() {
+		run, err := scanEvalRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+
+	return runs, rows.Err()
+}
+
+// GetEvalRun 获取评测任务及其全部结果
+func (m *Manager) GetEvalRun(id int64) (*models.EvalRun, error) {
+	row := m.db.QueryRow(`
+		SELECT id, name, periods_json, candidates_json, status, COALESCE(error, ''), created_at, finished_at
+		FROM eval_runs
+		WHERE id = ?
+	`, id)
+
+	run, err := scanEvalRun(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("eval run %d not found", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.Query(`
+		SELECT id, eval_run_id, period_start, period_end, candidate, provider, model, prompt,
+			COALESCE(screenshot_ids_json, ''), COALESCE(raw_response, ''), COALESCE(summary, ''),
+			json_valid, duration_plausible, total_minutes, latency_ms, COALESCE(error, ''), created_at
+		FROM eval_results
+		WHERE eval_run_id = ?
+		ORDER BY period_start ASC, id ASC
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query eval results: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		res := &models.EvalResult{}
+		var idsJSON string
+		err := rows.Scan(
+			&res.ID,
+			&res.EvalRunID,
+			&res.PeriodStart,
+			&res.PeriodEnd,
+			&res.Candidate,
+			&res.Provider,
+			&res.Model,
+			&res.Prompt,
+			&idsJSON,
+			&res.RawResponse,
+			&res.Summary,
+			&res.JSONValid,
+			&res.DurationPlausible,
+			&res.TotalMinutes,
+			&res.LatencyMs,
+			&res.Error,
+			&res.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan eval result: %w", err)
+		}
+		if idsJSON != "" {
+			if err := json.Unmarshal([]byte(idsJSON), &res.ScreenshotIDs); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal screenshot ids: %w", err)
+			}
+		}
+		run.Results = append(run.Results, res)
+	}
+
+	return run, rows.Err()
+}
+
+// scanEvalRun 读取一条评测任务记录
+func scanEvalRun(row rowScanner) (*models.EvalRun, error) {
+	run := &models.EvalRun{}
+	var periodsJSON, candidatesJSON string
+	var finishedAt sql.NullTime
+
+	err := row.Scan(
+		&run.ID,
+		&run.Name,
+		&periodsJSON,
+		&candidatesJSON,
+		&run.Status,
+		&run.Error,
+		&run.CreatedAt,
+		&finishedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan eval run: %w", err)
+	}
+
+	if finishedAt.Valid {
+		run.FinishedAt = &finishedAt.Time
+	}
+	if err := json.Unmarshal([]byte(periodsJSON), &run.Periods); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal periods: %w", err)
+	}
+	if err := json.Unmarshal([]byte(candidatesJSON), &run.Candidates); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal candidates: %w", err)
+	}
+
+	return run, nil
+}
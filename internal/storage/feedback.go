@@ -0,0 +1,146 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"WorkTrackerAI/pkg/models"
+)
+
+// SaveSummaryFeedback 保存总结评分（同一总结重复评分会覆盖旧评分）
+// 提供商与模型取自生成该总结的分析记录，用于按模型统计评分趋势
+func (m *Manager) SaveSummaryFeedback(fb *models.SummaryFeedback) error {
+	if fb.Provider == "" && fb.Model == "" {
+		err := m.db.QueryRow(
+			`SELECT provider, model FROM analysis_runs WHERE summary_id = ? ORDER BY id DESC LIMIT 1`,
+			fb.SummaryID,
+		).Scan(&fb.Provider, &fb.Model)
+		if err != nil {
+			// 早于分析记录功能的总结没有对应记录，模型记为未知
+			fb.Provider, fb.Model = "unknown", "unknown"
+		}
+	}
+
+	query := `
+		INSERT INTO summary_feedback (summary_id, rating, corrected_summary, provider, model, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(summary_id) DO UPDATE SET
+			rating = excluded.rating,
+			corrected_summary = excluded.corrected_summary,
+			updated_at = excluded.updated_at
+	`
+
+	_, err := m.db.Exec(query,
+		fb.SummaryID,
+		fb.Rating,
+		fb.CorrectedSummary,
+		fb.Provider,
+		fb.Model,
+		fb.CreatedAt,
+		fb.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save summary feedback: %w", err)
+	}
+
+	return m.db.QueryRow(`SELECT id FROM summary_feedback WHERE summary_id = ?`, fb.SummaryID).Scan(&fb.ID)
+}
+
+// GetFeedback 获取开始时间在 [start, end) 内的总结的评分
+func (m *Manager) GetFeedback(start, end time.Time) ([]*models.SummaryFeedback, error) {
+	rows, err := m.db.Query(`
+		SELECT f.id, f.summary_id, f.rating, COALESCE(f.corrected_summary, ''),
+			COALESCE(f.provider, ''), COALESCE(f.model, ''), f.created_at, f.updated_at
+		FROM summary_feedback f
+		JOIN work_summaries s ON s.id = f.summary_id
+		WHERE s.start_time >= ? AND s.start_time < ?
+		ORDER BY s.start_time ASC
+	`, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query summary feedback: %w", err)
+	}
+	defer rows.Close()
+
+	var feedback []*models.SummaryFeedback
+	for rows.Next() {
+		fb := &models.SummaryFeedback{}
+		err := rows.Scan(
+			&fb.ID,
+			&fb.SummaryID,
+			&fb.Rating,
+			&fb.CorrectedSummary,
+			&fb.Provider,
+			&fb.Model,
+			&fb.CreatedAt,
+			&fb.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan summary feedback: %w", err)
+		}
+		feedback = append(feedback, fb)
+	}
+
+	return feedback, rows.Err()
+}
+
+// GetFewShotExamples 获取评分最高的总结作为提示词示例
+// 优先使用用户修正后的版本，其次是好评的原始总结，按最近更新排序
+func (m *Manager) GetFewShotExamples(limit int) ([]string, error) {
+	rows, err := m.db.Query(`
+		SELECT CASE WHEN COALESCE(f.corrected_summary, '') <> '' THEN f.corrected_summary ELSE s.summary END
+		FROM summary_feedback f
+		JOIN work_summaries s ON s.id = f.summary_id
+		WHERE COALESCE(f.corrected_summary, '') <> '' OR f.rating > 0
+		ORDER BY (COALESCE(f.corrected_summary, '') <> '') DESC, f.rating DESC, f.updated_at DESC
+		LIMIT ?
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query few-shot examples: %w", err)
+	}
+	defer rows.Close()
+
+	var examples []string
+	for rows.Next() {
+		var example string
+		if err := rows.Scan(&example); err != nil {
+			return nil, fmt.Errorf("failed to scan few-shot example: %w", err)
+		}
+		examples = append(examples, example)
+	}
+
+	return examples, rows.Err()
+}
+
+// GetFeedbackTrends 按日期与模型统计评分趋势
+func (m *Manager) GetFeedbackTrends(since time.Time) ([]*models.FeedbackTrend, error) {
+	rows, err := m.db.Query(`
+		SELECT substr(s.start_time, 1, 10) AS day,
+			COALESCE(f.provider, ''), COALESCE(f.model, ''),
+			SUM(CASE WHEN f.rating > 0 THEN 1 ELSE 0 END),
+			SUM(CASE WHEN f.rating < 0 THEN 1 ELSE 0 END),
+			SUM(CASE WHEN COALESCE(f.corrected_summary, '') <> '' THEN 1 ELSE 0 END)
+		FROM summary_feedback f
+		JOIN work_summaries s ON s.id = f.summary_id
+		WHERE s.start_time >= ?
+		GROUP BY day, f.provider, f.model
+		ORDER BY day ASC, f.provider ASC, f.model ASC
+	`, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query feedback trends: %w", err)
+	}
+	defer rows.Close()
+
+	var trends []*models.FeedbackTrend
+	for rows.Next() {
+		t := &models.FeedbackTrend{}
+		if err := rows.Scan(&t.Date, &t.Provider, &t.Model, &t.Up, &t.Down, &t.Corrected); err != nil {
+			return nil, fmt.Errorf("failed to scan feedback trend: %w", err)
+		}
+		if total := t.Up + t.Down; total > 0 {
+			t.Score = float64(t.Up) / float64(total)
+		}
+		trends = append(trends, t)
+	}
+
+	return trends, rows.Err()
+}
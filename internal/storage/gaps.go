@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"WorkTrackerAI/pkg/models"
+)
+
+// SaveCaptureGap 保存截屏暂停时间段
+func (m *Manager) SaveCaptureGap(g *models.CaptureGap) error {
+	result, err := m.db.Exec(`
+		INSERT INTO capture_gaps (kind, start_time, end_time, open, reason, frames)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, g.Kind, g.StartTime, g.EndTime, g.Open, g.Reason, g.Frames)
+	if err != nil {
+		return fmt.Errorf("failed to insert capture gap: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get insert id: %w", err)
+	}
+
+	g.ID = id
+	return nil
+}
+
+// UpdateCaptureGap 更新暂停时间段的结束时间、进行状态与跳过次数
+func (m *Manager) UpdateCaptureGap(g *models.CaptureGap) error {
+	_, err := m.db.Exec(`UPDATE capture_gaps SET end_time = ?, open = ?, frames = ? WHERE id = ?`, g.EndTime, g.Open, g.Frames, g.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update capture gap: %w", err)
+	}
+	return nil
+}
+
+// CloseOpenCaptureGaps 结束指定原因下仍在进行中的暂停（如上次异常退出时遗留的记录）
+// 结束时间保留为最后一次检测的时间
+func (m *Manager) CloseOpenCaptureGaps(kind string) error {
+	_, err := m.db.Exec(`UPDATE capture_gaps SET open = 0 WHERE kind = ? AND open = 1`, kind)
+	if err != nil {
+		return fmt.Errorf("failed to close capture gaps: %w", err)
+	}
+	return nil
+}
+
+// GetCaptureGaps 获取与时间范围有重叠的暂停时间段（按开始时间排序）
+func (m *Manager) GetCaptureGaps(start, end time.Time) ([]*models.CaptureGap, error) {
+	rows, err := m.db.Query(`
+		SELECT id, kind, start_time, end_time, COALESCE(open, 0), COALESCE(reason, ''), COALESCE(frames, 0)
+		FROM capture_gaps
+		WHERE start_time < ? AND end_time > ?
+		ORDER BY start_time ASC
+	`, end, start)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query capture gaps: %w", err)
+	}
+	defer rows.Close()
+
+	var gaps []*models.CaptureGap
+	for rows.Next() {
+		g := &models.CaptureGap{}
+		if err := rows.Scan(&g.ID, &g.Kind, &g.StartTime, &g.EndTime, &g.Open, &g.Reason, &g.Frames); err != nil {
+			return nil, fmt.Errorf("failed to scan capture gap: %w", err)
+		}
+		gaps = append(gaps, g)
+	}
+
+	return gaps, rows.Err()
+}
+
+// GetOpenCaptureGap 获取指定原因下最近一条仍在进行中的暂停，没有时返回 nil
+func (m *Manager) GetOpenCaptureGap(kind string) (*models.CaptureGap, error) {
+	g := &models.CaptureGap{}
+	err := m.db.QueryRow(`
+		SELECT id, kind, start_time, end_time, COALESCE(open, 0), COALESCE(reason, ''), COALESCE(frames, 0)
+		FROM capture_gaps
+		WHERE kind = ? AND open = 1
+		ORDER BY start_time DESC
+		LIMIT 1
+	`, kind).Scan(&g.ID, &g.Kind, &g.StartTime, &g.EndTime, &g.Open, &g.Reason, &g.Frames)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query open capture gap: %w", err)
+	}
+	return g, nil
+}
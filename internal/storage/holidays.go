@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"WorkTrackerAI/pkg/clock"
+	"WorkTrackerAI/pkg/models"
+)
+
+// SaveHolidays 保存节假日日历条目（同一日期覆盖旧条目），并刷新缓存
+func (m *Manager) SaveHolidays(holidays []*models.Holiday) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := clock.Now()
+	for _, h := range holidays {
+		_, err := tx.Exec(`
+			INSERT INTO holidays (date, name, kind, source, created_at)
+			VALUES (?, ?, ?, ?, ?)
+			ON CONFLICT(date) DO UPDATE SET
+				name = excluded.name,
+				kind = excluded.kind,
+				source = excluded.source,
+				created_at = excluded.created_at
+		`, h.Date, h.Name, h.Kind, h.Source, now)
+		if err != nil {
+			return fmt.Errorf("failed to save holiday: %w", err)
+		}
+		h.CreatedAt = now
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit holidays: %w", err)
+	}
+	return m.loadHolidays()
+}
+
+// DeleteHoliday 删除指定日期的节假日日历条目，并刷新缓存
+func (m *Manager) DeleteHoliday(date string) (bool, error) {
+	result, err := m.db.Exec(`DELETE FROM holidays WHERE date = ?`, date)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete holiday: %w", err)
+	}
+	affected, _ := result.RowsAffected()
+	return affected > 0, m.loadHolidays()
+}
+
+// GetHolidays 获取日期范围内的节假日日历条目（日期格式 "2006-01-02"，包含两端）
+func (m *Manager) GetHolidays(from, to string) ([]*models.Holiday, error) {
+	rows, err := m.db.Query(`
+		SELECT date, COALESCE(name, ''), kind, COALESCE(source, ''), created_at
+		FROM holidays
+		WHERE date >= ? AND date <= ?
+		ORDER BY date ASC
+	`, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query holidays: %w", err)
+	}
+	defer rows.Close()
+
+	holidays := []*models.Holiday{}
+	for rows.Next() {
+		h := &models.Holiday{}
+		if err := rows.Scan(&h.Date, &h.Name, &h.Kind, &h.Source, &h.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan holiday: %w", err)
+		}
+		holidays = append(holidays, h)
+	}
+	return holidays, rows.Err()
+}
+
+// HolidayKind 查询某天在节假日日历中的类型（放假/调休上班），不在日历中时 ok 为 false
+// 使用内存缓存，可在截屏等高频路径中调用
+func (m *Manager) HolidayKind(date time.Time) (kind string, ok bool) {
+	m.holidayMu.RLock()
+	defer m.holidayMu.RUnlock()
+	kind, ok = m.holidays[date.Format("2006-01-02")]
+	return kind, ok
+}
+
+// loadHolidays 从数据库加载节假日缓存
+func (m *Manager) loadHolidays() error {
+	rows, err := m.db.Query(`SELECT date, kind FROM holidays`)
+	if err != nil {
+		return fmt.Errorf("failed to load holidays: %w", err)
+	}
+	defer rows.Close()
+
+	holidays := make(map[string]string)
+	for rows.Next() {
+		var date, kind string
+		if err := rows.Scan(&date, &kind); err != nil {
+			return fmt.Errorf("failed to scan holiday: %w", err)
+		}
+		holidays[date] = kind
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to load holidays: %w", err)
+	}
+
+	m.holidayMu.Lock()
+	m.holidays = holidays
+	m.holidayMu.Unlock()
+	return nil
+}
@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"fmt"
+
+	"WorkTrackerAI/pkg/clock"
+	"WorkTrackerAI/pkg/models"
+)
+
+// SaveJobRun 保存调度任务执行记录
+func (m *Manager) SaveJobRun(run *models.JobRun) error {
+	result, err := m.db.Exec(`
+		INSERT INTO job_runs (job_name, trigger, started_at, duration_ms, status, result, error)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, run.JobName, run.Trigger, run.StartedAt, run.DurationMs, run.Status, run.Result, run.Error)
+	if err != nil {
+		return fmt.Errorf("failed to save job run: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get job run id: %w", err)
+	}
+	run.ID = id
+	return nil
+}
+
+// GetJobRuns 获取调度任务执行记录（按时间倒序），jobName 为空时返回所有任务
+func (m *Manager) GetJobRuns(jobName string, limit int) ([]*models.JobRun, error) {
+	query := `
+		SELECT id, job_name, trigger, started_at, duration_ms, COALESCE(status, ''), COALESCE(result, ''), COALESCE(error, '')
+		FROM job_runs
+	`
+	args := []interface{}{}
+	if jobName != "" {
+		query += ` WHERE job_name = ?`
+		args = append(args, jobName)
+	}
+	query += ` ORDER BY started_at DESC, id DESC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := m.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query job runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []*models.JobRun
+	for rows.Next() {
+		run := &models.JobRun{}
+		err := rows.Scan(
+			&run.ID,
+			&run.JobName,
+			&run.Trigger,
+			&run.StartedAt,
+			&run.DurationMs,
+			&run.Status,
+			&run.Result,
+			&run.Error,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job run: %w", err)
+		}
+		runs = append(runs, run)
+	}
+
+	return runs, rows.Err()
+}
+
+// DeleteOldJobRuns 删除指定天数之前的调度任务执行记录
+func (m *Manager) DeleteOldJobRuns(retentionDays int) (int64, error) {
+	cutoff := clock.Now().AddDate(0, 0, -retentionDays)
+	result, err := m.db.Exec(`DELETE FROM job_runs WHERE started_at < ?`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete old job runs: %w", err)
+	}
+	return result.RowsAffected()
+}
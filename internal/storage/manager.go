@@ -6,8 +6,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
+	"WorkTrackerAI/pkg/clock"
 	"WorkTrackerAI/pkg/models"
 
 	_ "modernc.org/sqlite"
@@ -17,6 +20,9 @@ import (
 type Manager struct {
 	db     *sql.DB
 	dbPath string
+
+	holidayMu sync.RWMutex
+	holidays  map[string]string // 节假日缓存: 日期 -> 类型
 }
 
 // NewManager 创建存储管理器
@@ -43,6 +49,10 @@ func NewManager(dataDir string) (*Manager, error) {
 		return nil, fmt.Errorf("failed to init schema: %w", err)
 	}
 
+	if err := m.loadHolidays(); err != nil {
+		return nil, err
+	}
+
 	return m, nil
 }
 
@@ -56,7 +66,10 @@ func (m *Manager) initSchema() error {
 		file_path TEXT NOT NULL,
 		file_size INTEGER NOT NULL,
 		resolution TEXT,
+		format TEXT,
 		analyzed BOOLEAN DEFAULT 0,
+		unchanged BOOLEAN DEFAULT 0,
+		source_id INTEGER,
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
 	);
 
@@ -74,10 +87,222 @@ func (m *Manager) initSchema() error {
 	);
 
 	CREATE INDEX IF NOT EXISTS idx_summaries_date ON work_summaries(date(start_time));
+
+	CREATE TABLE IF NOT EXISTS analysis_runs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		start_time DATETIME NOT NULL,
+		end_time DATETIME NOT NULL,
+		provider TEXT NOT NULL,
+		model TEXT NOT NULL,
+		max_tokens INTEGER,
+		temperature REAL,
+		prompt TEXT NOT NULL,
+		screenshot_ids_json TEXT,
+		raw_response TEXT,
+		parse_status TEXT NOT NULL,
+		error TEXT,
+		summary_id INTEGER,
+		replay_of INTEGER,
+		latency_ms INTEGER,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_analysis_runs_start ON analysis_runs(start_time);
+
+	CREATE TABLE IF NOT EXISTS eval_runs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		periods_json TEXT NOT NULL,
+		candidates_json TEXT NOT NULL,
+		status TEXT NOT NULL,
+		error TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		finished_at DATETIME
+	);
+
+	CREATE TABLE IF NOT EXISTS eval_results (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		eval_run_id INTEGER NOT NULL,
+		period_start DATETIME NOT NULL,
+		period_end DATETIME NOT NULL,
+		candidate TEXT NOT NULL,
+		provider TEXT NOT NULL,
+		model TEXT NOT NULL,
+		prompt TEXT NOT NULL,
+		screenshot_ids_json TEXT,
+		raw_response TEXT,
+		summary TEXT,
+		json_valid BOOLEAN DEFAULT 0,
+		duration_plausible BOOLEAN DEFAULT 0,
+		total_minutes INTEGER DEFAULT 0,
+		latency_ms INTEGER DEFAULT 0,
+		error TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_eval_results_run ON eval_results(eval_run_id);
+
+	CREATE TABLE IF NOT EXISTS summary_feedback (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		summary_id INTEGER NOT NULL UNIQUE,
+		rating INTEGER NOT NULL,
+		corrected_summary TEXT,
+		provider TEXT,
+		model TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS screenshot_captions (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		screenshot_id INTEGER NOT NULL UNIQUE REFERENCES screenshots(id),
+		caption TEXT NOT NULL,
+		provider TEXT,
+		model TEXT,
+		analysis_run_id INTEGER,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS analysis_backlog (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		start_time DATETIME NOT NULL,
+		end_time DATETIME NOT NULL,
+		status TEXT NOT NULL,
+		reason TEXT,
+		attempts INTEGER DEFAULT 0,
+		last_error TEXT,
+		next_attempt_at DATETIME NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE(start_time, end_time)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_analysis_backlog_status ON analysis_backlog(status, next_attempt_at);
+
+	CREATE TABLE IF NOT EXISTS holidays (
+		date TEXT PRIMARY KEY,
+		name TEXT,
+		kind TEXT NOT NULL,
+		source TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS screenshot_windows (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		screenshot_id INTEGER NOT NULL UNIQUE REFERENCES screenshots(id),
+		title TEXT,
+		process_name TEXT,
+		pid INTEGER DEFAULT 0,
+		region_x REAL DEFAULT 0,
+		region_y REAL DEFAULT 0,
+		region_w REAL DEFAULT 0,
+		region_h REAL DEFAULT 0
+	);
+
+	CREATE TABLE IF NOT EXISTS segment_app_usage (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		summary_id INTEGER NOT NULL,
+		start_time DATETIME NOT NULL,
+		end_time DATETIME NOT NULL,
+		app TEXT NOT NULL,
+		title TEXT,
+		seconds INTEGER NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_segment_app_usage_summary ON segment_app_usage(summary_id);
+	CREATE INDEX IF NOT EXISTS idx_segment_app_usage_start ON segment_app_usage(start_time);
+
+	CREATE TABLE IF NOT EXISTS job_runs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		job_name TEXT NOT NULL,
+		trigger TEXT NOT NULL,
+		started_at DATETIME NOT NULL,
+		duration_ms INTEGER DEFAULT 0,
+		status TEXT DEFAULT 'success',
+		result TEXT,
+		error TEXT
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_job_runs_job ON job_runs(job_name, started_at);
+
+	CREATE TABLE IF NOT EXISTS capture_gaps (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		kind TEXT NOT NULL,
+		start_time DATETIME NOT NULL,
+		end_time DATETIME NOT NULL,
+		open BOOLEAN DEFAULT 1,
+		reason TEXT,
+		frames INTEGER DEFAULT 0
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_capture_gaps_start ON capture_gaps(start_time);
 	`
 
-	_, err := m.db.Exec(schema)
-	return err
+	if _, err := m.db.Exec(schema); err != nil {
+		return err
+	}
+
+	return m.migrateSchema()
+}
+
+// migrateSchema 为旧版本数据库补充新增的列
+func (m *Manager) migrateSchema() error {
+	columns := []struct {
+		table, column, definition string
+	}{
+		{"screenshots", "format", "TEXT"},
+		{"screenshots", "unchanged", "BOOLEAN DEFAULT 0"},
+		{"screenshots", "source_id", "INTEGER"},
+		{"job_runs", "status", "TEXT DEFAULT 'success'"},
+		{"capture_gaps", "reason", "TEXT"},
+		{"capture_gaps", "frames", "INTEGER DEFAULT 0"},
+		{"screenshot_windows", "region_x", "REAL DEFAULT 0"},
+		{"screenshot_windows", "region_y", "REAL DEFAULT 0"},
+		{"screenshot_windows", "region_w", "REAL DEFAULT 0"},
+		{"screenshot_windows", "region_h", "REAL DEFAULT 0"},
+	}
+
+	for _, c := range columns {
+		if err := m.addColumnIfMissing(c.table, c.column, c.definition); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addColumnIfMissing 列不存在时执行 ALTER TABLE 添加
+func (m *Manager) addColumnIfMissing(table, column, definition string) error {
+	rows, err := m.db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return fmt.Errorf("failed to inspect table %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid       int
+			name      string
+			colType   string
+			notNull   int
+			dfltValue sql.NullString
+			pk        int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dfltValue, &pk); err != nil {
+			return fmt.Errorf("failed to scan table info: %w", err)
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to inspect table %s: %w", table, err)
+	}
+	rows.Close()
+
+	if _, err := m.db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)); err != nil {
+		return fmt.Errorf("failed to add column %s.%s: %w", table, column, err)
+	}
+	return nil
 }
 
 // Close 关闭数据库
@@ -88,8 +313,8 @@ func (m *Manager) Close() error {
 // SaveScreenshot 保存截图记录
 func (m *Manager) SaveScreenshot(ss *models.Screenshot) error {
 	query := `
-		INSERT INTO screenshots (timestamp, screen_index, file_path, file_size, resolution, analyzed, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO screenshots (timestamp, screen_index, file_path, file_size, resolution, format, analyzed, unchanged, source_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	result, err := m.db.Exec(query,
@@ -98,7 +323,10 @@ func (m *Manager) SaveScreenshot(ss *models.Screenshot) error {
 		ss.FilePath,
 		ss.FileSize,
 		ss.Resolution,
+		ss.Format,
 		ss.Analyzed,
+		ss.Unchanged,
+		sql.NullInt64{Int64: ss.SourceID, Valid: ss.SourceID > 0},
 		ss.CreatedAt,
 	)
 
@@ -118,7 +346,7 @@ func (m *Manager) SaveScreenshot(ss *models.Screenshot) error {
 // GetScreenshots 获取指定时间范围的截图
 func (m *Manager) GetScreenshots(start, end time.Time) ([]*models.Screenshot, error) {
 	query := `
-		SELECT id, timestamp, screen_index, file_path, file_size, resolution, analyzed, created_at
+		SELECT id, timestamp, screen_index, file_path, file_size, resolution, COALESCE(format, ''), analyzed, COALESCE(unchanged, 0), COALESCE(source_id, 0), created_at
 		FROM screenshots
 		WHERE timestamp >= ? AND timestamp <= ?
 		ORDER BY timestamp ASC
@@ -130,32 +358,27 @@ func (m *Manager) GetScreenshots(start, end time.Time) ([]*models.Screenshot, er
 	}
 	defer rows.Close()
 
-	var screenshots []*models.Screenshot
-	for rows.Next() {
-		ss := &models.Screenshot{}
-		err := rows.Scan(
-			&ss.ID,
-			&ss.Timestamp,
-			&ss.ScreenIndex,
-			&ss.FilePath,
-			&ss.FileSize,
-			&ss.Resolution,
-			&ss.Analyzed,
-			&ss.CreatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan screenshot: %w", err)
-		}
-		screenshots = append(screenshots, ss)
-	}
+	return scanScreenshots(rows)
+}
 
-	return screenshots, nil
+// CountScreenshots 统计时间范围内的截图数量
+func (m *Manager) CountScreenshots(start, end time.Time) (int, error) {
+	var count int
+	err := m.db.QueryRow(
+		`SELECT COUNT(*) FROM screenshots WHERE timestamp >= ? AND timestamp < ?`,
+		start,
+		end,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count screenshots: %w", err)
+	}
+	return count, nil
 }
 
 // GetRecentScreenshots 获取最近的 N 个截图
 func (m *Manager) GetRecentScreenshots(limit int) ([]*models.Screenshot, error) {
 	query := `
-		SELECT id, timestamp, screen_index, file_path, file_size, resolution, analyzed, created_at
+		SELECT id, timestamp, screen_index, file_path, file_size, resolution, COALESCE(format, ''), analyzed, COALESCE(unchanged, 0), COALESCE(source_id, 0), created_at
 		FROM screenshots
 		ORDER BY timestamp DESC
 		LIMIT ?
@@ -167,6 +390,40 @@ func (m *Manager) GetRecentScreenshots(limit int) ([]*models.Screenshot, error)
 	}
 	defer rows.Close()
 
+	return scanScreenshots(rows)
+}
+
+// GetScreenshotsByIDs 按 ID 获取截图（按时间排序，不存在的 ID 会被忽略）
+func (m *Manager) GetScreenshotsByIDs(ids []int64) ([]*models.Screenshot, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	placeholders := make([]string, len(ids))
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, timestamp, screen_index, file_path, file_size, resolution, COALESCE(format, ''), analyzed, COALESCE(unchanged, 0), COALESCE(source_id, 0), created_at
+		FROM screenshots
+		WHERE id IN (%s)
+		ORDER BY timestamp ASC
+	`, strings.Join(placeholders, ","))
+
+	rows, err := m.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query screenshots: %w", err)
+	}
+	defer rows.Close()
+
+	return scanScreenshots(rows)
+}
+
+// scanScreenshots 将查询结果转换为截图列表
+func scanScreenshots(rows *sql.Rows) ([]*models.Screenshot, error) {
 	var screenshots []*models.Screenshot
 	for rows.Next() {
 		ss := &models.Screenshot{}
@@ -177,7 +434,10 @@ func (m *Manager) GetRecentScreenshots(limit int) ([]*models.Screenshot, error)
 			&ss.FilePath,
 			&ss.FileSize,
 			&ss.Resolution,
+			&ss.Format,
 			&ss.Analyzed,
+			&ss.Unchanged,
+			&ss.SourceID,
 			&ss.CreatedAt,
 		)
 		if err != nil {
@@ -186,7 +446,7 @@ func (m *Manager) GetRecentScreenshots(limit int) ([]*models.Screenshot, error)
 		screenshots = append(screenshots, ss)
 	}
 
-	return screenshots, nil
+	return screenshots, rows.Err()
 }
 
 // MarkScreenshotAnalyzed 标记截图已分析
@@ -198,11 +458,16 @@ func (m *Manager) MarkScreenshotAnalyzed(id int64) error {
 
 // DeleteOldScreenshots 删除旧截图
 func (m *Manager) DeleteOldScreenshots(retentionDays int) (int64, error) {
-	cutoffDate := time.Now().AddDate(0, 0, -retentionDays)
+	cutoffDate := clock.Now().AddDate(0, 0, -retentionDays)
 
 	// 首先获取要删除的截图文件路径
-	query := `SELECT file_path FROM screenshots WHERE timestamp < ?`
-	rows, err := m.db.Query(query, cutoffDate)
+	// 心跳记录不拥有文件；仍被保留期内心跳引用的文件暂不删除
+	query := `
+		SELECT DISTINCT file_path FROM screenshots
+		WHERE timestamp < ? AND COALESCE(unchanged, 0) = 0
+			AND file_path NOT IN (SELECT file_path FROM screenshots WHERE timestamp >= ?)
+	`
+	rows, err := m.db.Query(query, cutoffDate, cutoffDate)
 	if err != nil {
 		return 0, fmt.Errorf("failed to query old screenshots: %w", err)
 	}
@@ -223,6 +488,14 @@ func (m *Manager) DeleteOldScreenshots(retentionDays int) (int64, error) {
 		os.Remove(path) // 忽略错误
 	}
 
+	// 删除关联的截图描述与窗口信息
+	if _, err := m.db.Exec(`DELETE FROM screenshot_captions WHERE screenshot_id IN (SELECT id FROM screenshots WHERE timestamp < ?)`, cutoffDate); err != nil {
+		return 0, fmt.Errorf("failed to delete old captions: %w", err)
+	}
+	if _, err := m.db.Exec(`DELETE FROM screenshot_windows WHERE screenshot_id IN (SELECT id FROM screenshots WHERE timestamp < ?)`, cutoffDate); err != nil {
+		return 0, fmt.Errorf("failed to delete old windows: %w", err)
+	}
+
 	// 从数据库删除记录
 	deleteQuery := `DELETE FROM screenshots WHERE timestamp < ?`
 	result, err := m.db.Exec(deleteQuery, cutoffDate)
@@ -272,11 +545,8 @@ func (m *Manager) SaveWorkSummary(summary *models.WorkSummary) error {
 	return nil
 }
 
-// GetWorkSummaries 获取指定日期的工作总结
-func (m *Manager) GetWorkSummaries(date time.Time) ([]*models.WorkSummary, error) {
-	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
-	endOfDay := startOfDay.Add(24 * time.Hour)
-
+// GetWorkSummaries 获取开始时间在 [start, end) 内的工作总结（通常为一个逻辑日）
+func (m *Manager) GetWorkSummaries(start, end time.Time) ([]*models.WorkSummary, error) {
 	query := `
 		SELECT id, start_time, end_time, summary, activities_json, app_usage_json, created_at
 		FROM work_summaries
@@ -284,7 +554,7 @@ func (m *Manager) GetWorkSummaries(date time.Time) ([]*models.WorkSummary, error
 		ORDER BY start_time ASC
 	`
 
-	rows, err := m.db.Query(query, startOfDay, endOfDay)
+	rows, err := m.db.Query(query, start, end)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query work summaries: %w", err)
 	}
@@ -292,47 +562,74 @@ func (m *Manager) GetWorkSummaries(date time.Time) ([]*models.WorkSummary, error
 
 	var summaries []*models.WorkSummary
 	for rows.Next() {
-		ws := &models.WorkSummary{}
-		var activitiesJSON, appUsageJSON string
-
-		err := rows.Scan(
-			&ws.ID,
-			&ws.StartTime,
-			&ws.EndTime,
-			&ws.Summary,
-			&activitiesJSON,
-			&appUsageJSON,
-			&ws.CreatedAt,
-		)
+		ws, err := scanWorkSummary(rows)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan work summary: %w", err)
+			return nil, err
 		}
+		summaries = append(summaries, ws)
+	}
 
-		// 反序列化 JSON
-		if activitiesJSON != "" {
-			if err := json.Unmarshal([]byte(activitiesJSON), &ws.Activities); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal activities: %w", err)
-			}
-		}
+	return summaries, nil
+}
+
+// GetWorkSummary 按 ID 获取工作总结
+func (m *Manager) GetWorkSummary(id int64) (*models.WorkSummary, error) {
+	query := `
+		SELECT id, start_time, end_time, summary, activities_json, app_usage_json, created_at
+		FROM work_summaries
+		WHERE id = ?
+	`
 
-		if appUsageJSON != "" {
-			if err := json.Unmarshal([]byte(appUsageJSON), &ws.AppUsage); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal app usage: %w", err)
-			}
+	ws, err := scanWorkSummary(m.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("work summary %d not found", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ws, nil
+}
+
+// scanWorkSummary 读取一条工作总结并反序列化 JSON 字段
+func scanWorkSummary(row rowScanner) (*models.WorkSummary, error) {
+	ws := &models.WorkSummary{}
+	var activitiesJSON, appUsageJSON string
+
+	err := row.Scan(
+		&ws.ID,
+		&ws.StartTime,
+		&ws.EndTime,
+		&ws.Summary,
+		&activitiesJSON,
+		&appUsageJSON,
+		&ws.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan work summary: %w", err)
+	}
+
+	// 反序列化 JSON
+	if activitiesJSON != "" {
+		if err := json.Unmarshal([]byte(activitiesJSON), &ws.Activities); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal activities: %w", err)
 		}
+	}
 
-		summaries = append(summaries, ws)
+	if appUsageJSON != "" {
+		if err := json.Unmarshal([]byte(appUsageJSON), &ws.AppUsage); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal app usage: %w", err)
+		}
 	}
 
-	return summaries, nil
+	return ws, nil
 }
 
-// DeleteWorkSummariesForDate 删除指定日期的所有工作总结（用于“立即分析”重新生成）
-func (m *Manager) DeleteWorkSummariesForDate(date time.Time) error {
-	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
-	endOfDay := startOfDay.Add(24 * time.Hour)
-
-	_, err := m.db.Exec(`DELETE FROM work_summaries WHERE start_time >= ? AND start_time < ?`, startOfDay, endOfDay)
+// DeleteWorkSummaries 删除开始时间在 [start, end) 内的所有工作总结（用于“立即分析”重新生成）
+func (m *Manager) DeleteWorkSummaries(start, end time.Time) error {
+	_, err := m.db.Exec(`DELETE FROM work_summaries WHERE start_time >= ? AND start_time < ?`, start, end)
 	if err != nil {
 		return fmt.Errorf("failed to delete work summaries: %w", err)
 	}
@@ -409,19 +706,16 @@ func calculateDirSize(dirPath string) (int64, error) {
 	return totalSize, nil
 }
 
-// GetTodayStats 获取今日统计
-func (m *Manager) GetTodayStats() (screenshots int, summaries int, err error) {
-	now := time.Now()
-	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-
-	// 今日截图数
-	err = m.db.QueryRow(`SELECT COUNT(*) FROM screenshots WHERE timestamp >= ?`, startOfDay).Scan(&screenshots)
+// GetDayStats 获取 [start, end) 内的统计（通常为今天所在的逻辑日）
+func (m *Manager) GetDayStats(start, end time.Time) (screenshots int, summaries int, err error) {
+	// 截图数
+	err = m.db.QueryRow(`SELECT COUNT(*) FROM screenshots WHERE timestamp >= ? AND timestamp < ?`, start, end).Scan(&screenshots)
 	if err != nil {
 		return 0, 0, err
 	}
 
-	// 今日总结数
-	err = m.db.QueryRow(`SELECT COUNT(*) FROM work_summaries WHERE start_time >= ?`, startOfDay).Scan(&summaries)
+	// 总结数
+	err = m.db.QueryRow(`SELECT COUNT(*) FROM work_summaries WHERE start_time >= ? AND start_time < ?`, start, end).Scan(&summaries)
 	if err != nil {
 		return 0, 0, err
 	}
@@ -443,3 +737,21 @@ func (m *Manager) HasWorkSummaryForRange(start, end time.Time) (bool, error) {
 	}
 	return count > 0, nil
 }
+
+// SummaryCoveredUntil 获取时间段内已有工作总结覆盖到的最晚时间
+// 时间段内没有总结时返回零值（事件触发分析可能只覆盖了时间段的前半部分）
+func (m *Manager) SummaryCoveredUntil(start, end time.Time) (time.Time, error) {
+	var covered time.Time
+	err := m.db.QueryRow(
+		`SELECT end_time FROM work_summaries WHERE start_time >= ? AND start_time < ? ORDER BY end_time DESC LIMIT 1`,
+		start,
+		end,
+	).Scan(&covered)
+	if err == sql.ErrNoRows {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to query work summary coverage: %w", err)
+	}
+	return covered, nil
+}
@@ -0,0 +1,164 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"WorkTrackerAI/pkg/models"
+)
+
+// SaveScreenshotWindow 保存截图时的前台窗口信息
+func (m *Manager) SaveScreenshotWindow(w *models.ScreenshotWindow) error {
+	result, err := m.db.Exec(`
+		INSERT INTO screenshot_windows (screenshot_id, title, process_name, pid, region_x, region_y, region_w, region_h)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(screenshot_id) DO UPDATE SET
+			title = excluded.title,
+			process_name = excluded.process_name,
+			pid = excluded.pid,
+			region_x = excluded.region_x,
+			region_y = excluded.region_y,
+			region_w = excluded.region_w,
+			region_h = excluded.region_h
+	`, w.ScreenshotID, w.Title, w.ProcessName, w.PID, w.Region.X, w.Region.Y, w.Region.Width, w.Region.Height)
+	if err != nil {
+		return fmt.Errorf("failed to insert screenshot window: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get insert id: %w", err)
+	}
+
+	w.ID = id
+	return nil
+}
+
+// GetScreenshotWindows 获取指定时间范围内截图的前台窗口信息（按截图时间排序）
+func (m *Manager) GetScreenshotWindows(start, end time.Time) ([]*models.ScreenshotWindow, error) {
+	rows, err := m.db.Query(`
+		SELECT `+windowColumns+`
+		FROM screenshot_windows w
+		JOIN screenshots s ON s.id = w.screenshot_id
+		WHERE s.timestamp >= ? AND s.timestamp <= ?
+		ORDER BY s.timestamp ASC
+	`, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query screenshot windows: %w", err)
+	}
+	defer rows.Close()
+
+	return scanWindows(rows)
+}
+
+// GetScreenshotWindowsByIDs 获取指定截图的前台窗口信息（按截图 ID 索引）
+func (m *Manager) GetScreenshotWindowsByIDs(ids []int64) (map[int64]*models.ScreenshotWindow, error) {
+	byID := make(map[int64]*models.ScreenshotWindow, len(ids))
+	if len(ids) == 0 {
+		return byID, nil
+	}
+
+	placeholders := make([]string, len(ids))
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM screenshot_windows w
+		JOIN screenshots s ON s.id = w.screenshot_id
+		WHERE w.screenshot_id IN (%s)
+	`, windowColumns, strings.Join(placeholders, ","))
+
+	rows, err := m.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query screenshot windows: %w", err)
+	}
+	defer rows.Close()
+
+	windows, err := scanWindows(rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range windows {
+		byID[w.ScreenshotID] = w
+	}
+	return byID, nil
+}
+
+// windowColumns 前台窗口查询的列（w 为 screenshot_windows，s 为 screenshots）
+const windowColumns = `w.id, w.screenshot_id, s.timestamp, COALESCE(w.title, ''), COALESCE(w.process_name, ''), COALESCE(w.pid, 0),
+		COALESCE(w.region_x, 0), COALESCE(w.region_y, 0), COALESCE(w.region_w, 0), COALESCE(w.region_h, 0)`
+
+// scanWindows 读取前台窗口查询结果
+func scanWindows(rows *sql.Rows) ([]*models.ScreenshotWindow, error) {
+	var windows []*models.ScreenshotWindow
+	for rows.Next() {
+		w := &models.ScreenshotWindow{}
+		if err := rows.Scan(&w.ID, &w.ScreenshotID, &w.Timestamp, &w.Title, &w.ProcessName, &w.PID,
+			&w.Region.X, &w.Region.Y, &w.Region.Width, &w.Region.Height); err != nil {
+			return nil, fmt.Errorf("failed to scan screenshot window: %w", err)
+		}
+		windows = append(windows, w)
+	}
+	return windows, rows.Err()
+}
+
+// SaveSegmentAppUsage 保存总结对应时间段的实测使用时长（覆盖该总结已有的记录）
+func (m *Manager) SaveSegmentAppUsage(summaryID int64, rows []*models.SegmentAppUsage) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM segment_app_usage WHERE summary_id = ?`, summaryID); err != nil {
+		return fmt.Errorf("failed to delete segment app usage: %w", err)
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO segment_app_usage (summary_id, start_time, end_time, app, title, seconds)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare app usage insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, r := range rows {
+		if _, err := stmt.Exec(summaryID, r.StartTime, r.EndTime, r.App, r.Title, r.Seconds); err != nil {
+			return fmt.Errorf("failed to insert app usage: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetSegmentAppUsage 获取开始时间在范围内的时间段实测使用时长
+func (m *Manager) GetSegmentAppUsage(start, end time.Time) ([]*models.SegmentAppUsage, error) {
+	rows, err := m.db.Query(`
+		SELECT id, summary_id, start_time, end_time, app, COALESCE(title, ''), seconds
+		FROM segment_app_usage
+		WHERE start_time >= ? AND start_time < ?
+		ORDER BY start_time ASC
+	`, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query segment app usage: %w", err)
+	}
+	defer rows.Close()
+
+	var usage []*models.SegmentAppUsage
+	for rows.Next() {
+		u := &models.SegmentAppUsage{}
+		if err := rows.Scan(&u.ID, &u.SummaryID, &u.StartTime, &u.EndTime, &u.App, &u.Title, &u.Seconds); err != nil {
+			return nil, fmt.Errorf("failed to scan segment app usage: %w", err)
+		}
+		usage = append(usage, u)
+	}
+
+	return usage, rows.Err()
+}
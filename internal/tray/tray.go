@@ -55,6 +55,13 @@ func (t *TrayApp) onReady() {
 	// 打开 Web 管理界面
 	mOpen := systray.AddMenuItem("🌐 打开管理界面", "在浏览器中打开 Web 管理页面")
 
+	// 私密模式：暂停截屏一段时间，到期自动恢复
+	mPrivate := systray.AddMenuItem("🔒 私密模式", "暂停截屏一段时间")
+	mPause15 := mPrivate.AddSubMenuItem("暂停 15 分钟", "15 分钟后自动恢复截屏")
+	mPause60 := mPrivate.AddSubMenuItem("暂停 1 小时", "1 小时后自动恢复截屏")
+	mPauseTomorrow := mPrivate.AddSubMenuItem("暂停到明天", "到次日（按逻辑日分界时间）自动恢复截屏")
+	mResume := mPrivate.AddSubMenuItem("▶️ 恢复截屏", "立即结束私密模式")
+
 	systray.AddSeparator()
 
 	// 退出程序
@@ -68,6 +75,22 @@ func (t *TrayApp) onReady() {
 				fmt.Println("📱 打开浏览器...")
 				t.openBrowser()
 
+			case <-mPause15.ClickedCh:
+				t.pause("15m")
+
+			case <-mPause60.ClickedCh:
+				t.pause("1h")
+
+			case <-mPauseTomorrow.ClickedCh:
+				t.pause(capture.PauseTomorrow)
+
+			case <-mResume.ClickedCh:
+				if t.captureEng.Resume() {
+					fmt.Println("▶️ 私密模式已结束，恢复截屏")
+				} else {
+					fmt.Println("ℹ️ 当前未处于私密模式")
+				}
+
 			case <-mQuit.ClickedCh:
 				fmt.Println("🛑 用户请求退出...")
 				systray.Quit()
@@ -112,6 +135,19 @@ func (t *TrayApp) onQuit() {
 	fmt.Println("👋 WorkTracker 已退出")
 }
 
+// pause 开启私密模式，option 为时长或 capture.PauseTomorrow
+func (t *TrayApp) pause(option string) {
+	until, err := t.captureEng.PauseEnd(option)
+	if err == nil {
+		_, err = t.captureEng.Pause(until)
+	}
+	if err != nil {
+		fmt.Printf("⚠️ 开启私密模式失败: %v\n", err)
+		return
+	}
+	fmt.Printf("🔒 私密模式已开启，截屏暂停至 %s\n", until.Format("01-02 15:04"))
+}
+
 // openBrowser 打开浏览器
 func (t *TrayApp) openBrowser() {
 	var cmd *exec.Cmd
@@ -0,0 +1,75 @@
+package activewindow
+
+import (
+	"errors"
+	"image"
+	"sync"
+)
+
+var (
+	// ErrUnsupported 当前平台不支持获取前台窗口
+	ErrUnsupported = errors.New("当前平台不支持获取前台窗口")
+	// ErrNoWindow 当前没有前台窗口（如桌面或锁屏）
+	ErrNoWindow = errors.New("没有前台窗口")
+)
+
+// Info 前台窗口信息
+type Info struct {
+	Title       string // 窗口标题
+	ProcessName string // 进程名（如 chrome.exe、code）
+	PID         int    // 进程 ID
+
+	Bounds image.Rectangle // 窗口在屏幕上的区域（含标题栏，虚拟桌面坐标），获取失败时为空
+}
+
+// Fake 可手动设置的前台窗口，用于测试与模拟运行
+type Fake struct {
+	mu   sync.RWMutex
+	info *Info
+	err  error
+}
+
+// NewFake 创建返回指定窗口的 Fake
+func NewFake(title, processName string, pid int) *Fake {
+	f := &Fake{}
+	f.Set(title, processName, pid)
+	return f
+}
+
+// ActiveWindow 返回当前设置的窗口
+func (f *Fake) ActiveWindow() (*Info, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	if f.info == nil {
+		return nil, ErrNoWindow
+	}
+	info := *f.info
+	return &info, nil
+}
+
+// Set 切换前台窗口
+func (f *Fake) Set(title, processName string, pid int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.info = &Info{Title: title, ProcessName: processName, PID: pid}
+	f.err = nil
+}
+
+// SetBounds 设置当前窗口在屏幕上的区域
+func (f *Fake) SetBounds(bounds image.Rectangle) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.info != nil {
+		f.info.Bounds = bounds
+	}
+}
+
+// SetError 让后续调用返回指定错误
+func (f *Fake) SetError(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
@@ -0,0 +1,139 @@
+//go:build linux
+// +build linux
+
+package activewindow
+
+import (
+	"fmt"
+	"image"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/jezek/xgb"
+	"github.com/jezek/xgb/xproto"
+)
+
+// x11 到 X 服务器的长连接，断开后在下次调用时重连
+var x11 struct {
+	mu    sync.Mutex
+	conn  *xgb.Conn
+	root  xproto.Window
+	atoms map[string]xproto.Atom
+}
+
+// Current 通过 EWMH 属性获取当前前台窗口的标题、进程名与 PID（需要 X11 会话）
+func Current() (*Info, error) {
+	x11.mu.Lock()
+	defer x11.mu.Unlock()
+
+	if x11.conn == nil {
+		conn, err := xgb.NewConn()
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to X server: %w", err)
+		}
+		x11.conn = conn
+		x11.root = xproto.Setup(conn).DefaultScreen(conn).Root
+		x11.atoms = make(map[string]xproto.Atom)
+	}
+
+	info, err := activeWindow()
+	if err != nil && err != ErrNoWindow {
+		// 连接可能已失效（如 X 服务器重启），下次重新连接
+		x11.conn.Close()
+		x11.conn = nil
+	}
+	return info, err
+}
+
+// activeWindow 读取 _NET_ACTIVE_WINDOW 指向的窗口信息
+func activeWindow() (*Info, error) {
+	reply, err := getProperty(x11.root, "_NET_ACTIVE_WINDOW", xproto.AtomWindow, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(reply.Value) < 4 {
+		return nil, ErrNoWindow
+	}
+	win := xproto.Window(xgb.Get32(reply.Value))
+	if win == 0 {
+		return nil, ErrNoWindow
+	}
+
+	info := &Info{}
+
+	// 优先使用 UTF-8 标题，旧程序只设置 WM_NAME
+	utf8, err := atom("UTF8_STRING")
+	if err != nil {
+		return nil, err
+	}
+	if title, err := getProperty(win, "_NET_WM_NAME", utf8, 1024); err == nil && len(title.Value) > 0 {
+		info.Title = string(title.Value)
+	} else if title, err := xproto.GetProperty(x11.conn, false, win, xproto.AtomWmName, xproto.GetPropertyTypeAny, 0, 1024).Reply(); err == nil {
+		info.Title = string(title.Value)
+	}
+
+	if pid, err := getProperty(win, "_NET_WM_PID", xproto.AtomCardinal, 1); err == nil && len(pid.Value) >= 4 {
+		info.PID = int(xgb.Get32(pid.Value))
+		info.ProcessName = processName(info.PID)
+	}
+	info.Bounds = windowBounds(win)
+	return info, nil
+}
+
+// windowBounds 获取窗口在根窗口中的区域，并按 _NET_FRAME_EXTENTS 扩展到包含窗口管理器绘制的标题栏
+func windowBounds(win xproto.Window) image.Rectangle {
+	geom, err := xproto.GetGeometry(x11.conn, xproto.Drawable(win)).Reply()
+	if err != nil {
+		return image.Rectangle{}
+	}
+	pos, err := xproto.TranslateCoordinates(x11.conn, win, x11.root, 0, 0).Reply()
+	if err != nil {
+		return image.Rectangle{}
+	}
+	bounds := image.Rect(int(pos.DstX), int(pos.DstY), int(pos.DstX)+int(geom.Width), int(pos.DstY)+int(geom.Height))
+
+	// 顺序为 left, right, top, bottom
+	if ext, err := getProperty(win, "_NET_FRAME_EXTENTS", xproto.AtomCardinal, 4); err == nil && len(ext.Value) >= 16 {
+		bounds.Min.X -= int(xgb.Get32(ext.Value[0:]))
+		bounds.Max.X += int(xgb.Get32(ext.Value[4:]))
+		bounds.Min.Y -= int(xgb.Get32(ext.Value[8:]))
+		bounds.Max.Y += int(xgb.Get32(ext.Value[12:]))
+	}
+	return bounds
+}
+
+// getProperty 读取窗口属性，length 以 32 位为单位
+func getProperty(win xproto.Window, name string, typ xproto.Atom, length uint32) (*xproto.GetPropertyReply, error) {
+	prop, err := atom(name)
+	if err != nil {
+		return nil, err
+	}
+	reply, err := xproto.GetProperty(x11.conn, false, win, prop, typ, 0, length).Reply()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get property %s: %w", name, err)
+	}
+	return reply, nil
+}
+
+// atom 获取属性名对应的 Atom（带缓存）
+func atom(name string) (xproto.Atom, error) {
+	if a, ok := x11.atoms[name]; ok {
+		return a, nil
+	}
+	reply, err := xproto.InternAtom(x11.conn, false, uint16(len(name)), name).Reply()
+	if err != nil {
+		return 0, fmt.Errorf("failed to intern atom %s: %w", name, err)
+	}
+	x11.atoms[name] = reply.Atom
+	return reply.Atom, nil
+}
+
+// processName 从 /proc 读取进程名
+func processName(pid int) string {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/comm", pid))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
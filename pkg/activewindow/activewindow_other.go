@@ -0,0 +1,9 @@
+//go:build !windows && !linux
+// +build !windows,!linux
+
+package activewindow
+
+// Current 获取当前前台窗口（该平台暂不支持）
+func Current() (*Info, error) {
+	return nil, ErrUnsupported
+}
@@ -0,0 +1,88 @@
+//go:build windows
+// +build windows
+
+package activewindow
+
+import (
+	"image"
+	"path/filepath"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	user32                         = syscall.NewLazyDLL("user32.dll")
+	kernel32                       = syscall.NewLazyDLL("kernel32.dll")
+	procGetForegroundWindow        = user32.NewProc("GetForegroundWindow")
+	procGetWindowTextLengthW       = user32.NewProc("GetWindowTextLengthW")
+	procGetWindowTextW             = user32.NewProc("GetWindowTextW")
+	procGetWindowThreadProcessId   = user32.NewProc("GetWindowThreadProcessId")
+	procGetWindowRect              = user32.NewProc("GetWindowRect")
+	procOpenProcess                = kernel32.NewProc("OpenProcess")
+	procQueryFullProcessImageNameW = kernel32.NewProc("QueryFullProcessImageNameW")
+	procCloseHandle                = kernel32.NewProc("CloseHandle")
+)
+
+const PROCESS_QUERY_LIMITED_INFORMATION = 0x1000
+
+// Current 获取当前前台窗口的标题、进程名与 PID
+func Current() (*Info, error) {
+	hwnd, _, _ := procGetForegroundWindow.Call()
+	if hwnd == 0 {
+		return nil, ErrNoWindow
+	}
+
+	info := &Info{Title: windowText(hwnd), Bounds: windowRect(hwnd)}
+
+	var pid uint32
+	procGetWindowThreadProcessId.Call(hwnd, uintptr(unsafe.Pointer(&pid)))
+	if pid != 0 {
+		info.PID = int(pid)
+		info.ProcessName = processName(pid)
+	}
+	return info, nil
+}
+
+// windowText 读取窗口标题
+func windowText(hwnd uintptr) string {
+	n, _, _ := procGetWindowTextLengthW.Call(hwnd)
+	if n == 0 {
+		return ""
+	}
+
+	buf := make([]uint16, n+1)
+	procGetWindowTextW.Call(hwnd, uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)))
+	return syscall.UTF16ToString(buf)
+}
+
+// windowRect 读取窗口在屏幕上的区域（含标题栏与边框）
+func windowRect(hwnd uintptr) image.Rectangle {
+	var rect struct{ Left, Top, Right, Bottom int32 }
+	ret, _, _ := procGetWindowRect.Call(hwnd, uintptr(unsafe.Pointer(&rect)))
+	if ret == 0 {
+		return image.Rectangle{}
+	}
+	return image.Rect(int(rect.Left), int(rect.Top), int(rect.Right), int(rect.Bottom))
+}
+
+// processName 根据 PID 获取可执行文件名，权限不足时返回空字符串
+func processName(pid uint32) string {
+	handle, _, _ := procOpenProcess.Call(PROCESS_QUERY_LIMITED_INFORMATION, 0, uintptr(pid))
+	if handle == 0 {
+		return ""
+	}
+	defer procCloseHandle.Call(handle)
+
+	buf := make([]uint16, 1024)
+	size := uint32(len(buf))
+	ret, _, _ := procQueryFullProcessImageNameW.Call(
+		handle,
+		0,
+		uintptr(unsafe.Pointer(&buf[0])),
+		uintptr(unsafe.Pointer(&size)),
+	)
+	if ret == 0 {
+		return ""
+	}
+	return filepath.Base(syscall.UTF16ToString(buf[:size]))
+}
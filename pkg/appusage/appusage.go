@@ -0,0 +1,176 @@
+package appusage
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"WorkTrackerAI/pkg/models"
+)
+
+// 同一轮截屏（多屏幕分别保存）内的采样时间差上限，只计一次
+const sameRound = time.Second
+
+// 没有进程名时使用的应用名
+const unknownApp = "未知应用"
+
+// 报告的数据来源
+const (
+	SourceSamples  = "samples"
+	SourceSegments = "segments"
+)
+
+// Compute 根据前台窗口采样计算各应用与窗口标题的使用时长
+// 每个采样代表到下一次采样为止的时间，最后一个采样计到 end；
+// 单个采样最多计 maxGap，超出部分（锁屏、停止截屏等）不计入；
+// 采样时间在截屏暂停时段（离开、隐私规则、私密模式）之前截止，处于暂停时段内的采样不计入
+func Compute(samples []*models.ScreenshotWindow, gaps []*models.CaptureGap, start, end time.Time, maxGap time.Duration) *models.AppUsageReport {
+	report := &models.AppUsageReport{Start: start, End: end, Source: SourceSamples, Apps: []models.AppUsageEntry{}}
+
+	// 多屏幕在同一轮截屏中记录的窗口相同，只保留第一条
+	rounds := make([]*models.ScreenshotWindow, 0, len(samples))
+	for _, s := range samples {
+		if s.Timestamp.Before(start) || !s.Timestamp.Before(end) {
+			continue
+		}
+		if n := len(rounds); n > 0 && s.Timestamp.Sub(rounds[n-1].Timestamp) < sameRound {
+			continue
+		}
+		rounds = append(rounds, s)
+	}
+
+	apps := make(map[string]map[string]int64)
+	for i, s := range rounds {
+		next := end
+		if i+1 < len(rounds) {
+			next = rounds[i+1].Timestamp
+		}
+		if maxGap > 0 && next.Sub(s.Timestamp) > maxGap {
+			next = s.Timestamp.Add(maxGap)
+		}
+		next = clipAtGap(s.Timestamp, next, gaps)
+		seconds := int64(next.Sub(s.Timestamp).Seconds())
+		if seconds <= 0 {
+			continue
+		}
+
+		app := AppName(s.ProcessName)
+		if apps[app] == nil {
+			apps[app] = make(map[string]int64)
+		}
+		apps[app][s.Title] += seconds
+		report.TotalSeconds += seconds
+	}
+	report.Samples = len(rounds)
+	report.Apps = sortedEntries(apps)
+	return report
+}
+
+// clipAtGap 返回采样 [from, to) 在暂停时段处截止后的结束时间，from 处于暂停时段内时返回 from
+func clipAtGap(from, to time.Time, gaps []*models.CaptureGap) time.Time {
+	for _, g := range gaps {
+		if !g.EndTime.After(from) || !g.StartTime.Before(to) {
+			continue
+		}
+		if !g.StartTime.After(from) {
+			return from
+		}
+		to = g.StartTime
+	}
+	return to
+}
+
+// sortedEntries 将 应用 -> 标题 -> 秒数 转换为按时长降序的列表
+func sortedEntries(apps map[string]map[string]int64) []models.AppUsageEntry {
+	entries := make([]models.AppUsageEntry, 0, len(apps))
+	for app, titles := range apps {
+		entry := models.AppUsageEntry{App: app, Titles: make([]models.TitleUsageEntry, 0, len(titles))}
+		for title, seconds := range titles {
+			entry.Seconds += seconds
+			entry.Titles = append(entry.Titles, models.TitleUsageEntry{Title: title, Seconds: seconds})
+		}
+		sort.Slice(entry.Titles, func(i, j int) bool {
+			if entry.Titles[i].Seconds != entry.Titles[j].Seconds {
+				return entry.Titles[i].Seconds > entry.Titles[j].Seconds
+			}
+			return entry.Titles[i].Title < entry.Titles[j].Title
+		})
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Seconds != entries[j].Seconds {
+			return entries[i].Seconds > entries[j].Seconds
+		}
+		return entries[i].App < entries[j].App
+	})
+	return entries
+}
+
+// MaxGap 单个采样最多代表的时长：实际截屏间隔上限的两倍
+func MaxGap(cfg models.CaptureConfig) time.Duration {
+	interval := cfg.Interval
+	if cfg.AdaptiveInterval && cfg.MaxInterval > interval {
+		interval = cfg.MaxInterval
+	}
+	if interval <= 0 {
+		return 0
+	}
+	return 2 * time.Duration(interval) * time.Second
+}
+
+// AppName 由进程名得到应用名（去掉 .exe 后缀）
+func AppName(processName string) string {
+	name := strings.TrimSpace(processName)
+	if strings.HasSuffix(strings.ToLower(name), ".exe") {
+		name = name[:len(name)-len(".exe")]
+	}
+	if name == "" {
+		return unknownApp
+	}
+	return name
+}
+
+// Minutes 转换为 WorkSummary.AppUsage 使用的分钟数（四舍五入，不足半分钟的应用不计入）
+func Minutes(report *models.AppUsageReport) map[string]int {
+	minutes := make(map[string]int, len(report.Apps))
+	for _, app := range report.Apps {
+		if m := int((app.Seconds + 30) / 60); m > 0 {
+			minutes[app.App] = m
+		}
+	}
+	return minutes
+}
+
+// FromRows 由已存储的时间段记录汇总报告（采样记录已被清理时使用）
+func FromRows(rows []*models.SegmentAppUsage, start, end time.Time) *models.AppUsageReport {
+	report := &models.AppUsageReport{Start: start, End: end, Source: SourceSegments, Apps: []models.AppUsageEntry{}}
+
+	apps := make(map[string]map[string]int64)
+	for _, r := range rows {
+		if apps[r.App] == nil {
+			apps[r.App] = make(map[string]int64)
+		}
+		apps[r.App][r.Title] += r.Seconds
+		report.TotalSeconds += r.Seconds
+	}
+	report.Apps = sortedEntries(apps)
+	return report
+}
+
+// Rows 展开为按时间段存储的记录（每个应用 + 窗口标题一条）
+func Rows(report *models.AppUsageReport, summaryID int64) []*models.SegmentAppUsage {
+	var rows []*models.SegmentAppUsage
+	for _, app := range report.Apps {
+		for _, title := range app.Titles {
+			rows = append(rows, &models.SegmentAppUsage{
+				SummaryID: summaryID,
+				StartTime: report.Start,
+				EndTime:   report.End,
+				App:       app.App,
+				Title:     title.Title,
+				Seconds:   title.Seconds,
+			})
+		}
+	}
+	return rows
+}
@@ -0,0 +1,171 @@
+package appusage
+
+import (
+	"testing"
+	"time"
+
+	"WorkTrackerAI/pkg/models"
+)
+
+func TestCompute(t *testing.T) {
+	base := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) time.Time { return base.Add(d) }
+	sample := func(d time.Duration, process, title string) *models.ScreenshotWindow {
+		return &models.ScreenshotWindow{Timestamp: at(d), ProcessName: process, Title: title}
+	}
+
+	tests := []struct {
+		name    string
+		samples []*models.ScreenshotWindow
+		gaps    []*models.CaptureGap
+		end     time.Duration
+		maxGap  time.Duration
+		want    map[string]int64
+		total   int64
+		rounds  int
+	}{
+		{
+			name: "每个采样计到下一次采样，最后一个计到结束",
+			samples: []*models.ScreenshotWindow{
+				sample(0, "code", "main.go"),
+				sample(time.Minute, "chrome.exe", "A"),
+				sample(2*time.Minute, "Chrome.EXE", "B"),
+			},
+			end:    3 * time.Minute,
+			maxGap: 5 * time.Minute,
+			want:   map[string]int64{"code": 60, "chrome": 60, "Chrome": 60},
+			total:  180,
+			rounds: 3,
+		},
+		{
+			name: "同一轮多屏幕的采样只计一次",
+			samples: []*models.ScreenshotWindow{
+				sample(0, "code", "main.go"),
+				sample(500*time.Millisecond, "code", "main.go"),
+				sample(time.Minute, "chrome", "A"),
+			},
+			end:    2 * time.Minute,
+			maxGap: 5 * time.Minute,
+			want:   map[string]int64{"code": 60, "chrome": 60},
+			total:  120,
+			rounds: 2,
+		},
+		{
+			name: "单个采样最多计 maxGap",
+			samples: []*models.ScreenshotWindow{
+				sample(0, "code", "main.go"),
+				sample(10*time.Minute, "chrome", "A"),
+			},
+			end:    11 * time.Minute,
+			maxGap: 2 * time.Minute,
+			want:   map[string]int64{"code": 120, "chrome": 60},
+			total:  180,
+			rounds: 2,
+		},
+		{
+			name: "maxGap 为 0 时不限制",
+			samples: []*models.ScreenshotWindow{
+				sample(0, "code", "main.go"),
+			},
+			end:    30 * time.Minute,
+			want:   map[string]int64{"code": 1800},
+			total:  1800,
+			rounds: 1,
+		},
+		{
+			name: "采样在暂停时段开始处截止，暂停时段内的采样不计入",
+			samples: []*models.ScreenshotWindow{
+				sample(0, "code", "main.go"),
+				sample(2*time.Minute, "chrome", "A"),
+				sample(3*time.Minute, "slack", "general"),
+			},
+			gaps: []*models.CaptureGap{
+				{Kind: models.GapPrivate, StartTime: at(time.Minute), EndTime: at(3 * time.Minute)},
+			},
+			end:    4 * time.Minute,
+			maxGap: 5 * time.Minute,
+			want:   map[string]int64{"code": 60, "slack": 60},
+			total:  120,
+			rounds: 3,
+		},
+		{
+			name: "忽略范围外的采样",
+			samples: []*models.ScreenshotWindow{
+				sample(-time.Minute, "code", "main.go"),
+				sample(0, "chrome", "A"),
+				sample(time.Minute, "slack", "general"),
+			},
+			end:    time.Minute,
+			maxGap: 5 * time.Minute,
+			want:   map[string]int64{"chrome": 60},
+			total:  60,
+			rounds: 1,
+		},
+		{
+			name: "没有进程名时记为未知应用",
+			samples: []*models.ScreenshotWindow{
+				sample(0, "", "untitled"),
+			},
+			end:    time.Minute,
+			maxGap: 5 * time.Minute,
+			want:   map[string]int64{unknownApp: 60},
+			total:  60,
+			rounds: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Compute(tt.samples, tt.gaps, base, at(tt.end), tt.maxGap)
+			if report.TotalSeconds != tt.total {
+				t.Errorf("TotalSeconds = %d, want %d", report.TotalSeconds, tt.total)
+			}
+			if report.Samples != tt.rounds {
+				t.Errorf("Samples = %d, want %d", report.Samples, tt.rounds)
+			}
+
+			got := make(map[string]int64, len(report.Apps))
+			for _, app := range report.Apps {
+				got[app.App] = app.Seconds
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("apps = %v, want %v", got, tt.want)
+			}
+			for app, seconds := range tt.want {
+				if got[app] != seconds {
+					t.Errorf("%s = %ds, want %ds", app, got[app], seconds)
+				}
+			}
+		})
+	}
+}
+
+func TestClipAtGap(t *testing.T) {
+	base := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return base.Add(time.Duration(minutes) * time.Minute) }
+	gap := func(from, to int) *models.CaptureGap {
+		return &models.CaptureGap{StartTime: at(from), EndTime: at(to)}
+	}
+
+	tests := []struct {
+		name     string
+		from, to int
+		gaps     []*models.CaptureGap
+		want     int
+	}{
+		{"没有暂停时段", 0, 5, nil, 5},
+		{"暂停时段在采样之后", 0, 5, []*models.CaptureGap{gap(5, 8)}, 5},
+		{"暂停时段在采样之前结束", 3, 5, []*models.CaptureGap{gap(0, 3)}, 5},
+		{"在暂停时段开始处截止", 0, 5, []*models.CaptureGap{gap(2, 8)}, 2},
+		{"取最早的暂停时段", 0, 5, []*models.CaptureGap{gap(3, 4), gap(1, 2)}, 1},
+		{"采样处于暂停时段内", 2, 5, []*models.CaptureGap{gap(1, 3)}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := clipAtGap(at(tt.from), at(tt.to), tt.gaps); !got.Equal(at(tt.want)) {
+				t.Errorf("clipAtGap = %s, want %s", got.Format("15:04"), at(tt.want).Format("15:04"))
+			}
+		})
+	}
+}
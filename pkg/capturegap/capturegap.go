@@ -0,0 +1,67 @@
+package capturegap
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"WorkTrackerAI/pkg/models"
+)
+
+// Report 将暂停时间段截断到 [start, end) 范围内，并按暂停原因汇总时长
+func Report(gaps []*models.CaptureGap, start, end time.Time) *models.CaptureGapReport {
+	report := &models.CaptureGapReport{
+		Start:        start,
+		End:          end,
+		Gaps:         Clip(gaps, start, end),
+		TotalSeconds: make(map[string]int64),
+	}
+	for _, g := range report.Gaps {
+		report.TotalSeconds[g.Kind] += int64(g.EndTime.Sub(g.StartTime).Seconds())
+	}
+	return report
+}
+
+// Clip 返回截断到 [start, end) 范围内的暂停时间段副本，不在范围内的时间段被丢弃
+func Clip(gaps []*models.CaptureGap, start, end time.Time) []*models.CaptureGap {
+	clipped := make([]*models.CaptureGap, 0, len(gaps))
+	for _, g := range gaps {
+		c := *g
+		if c.StartTime.Before(start) {
+			c.StartTime = start
+		}
+		if c.EndTime.After(end) {
+			c.EndTime = end
+		}
+		if !c.EndTime.After(c.StartTime) {
+			continue
+		}
+		clipped = append(clipped, &c)
+	}
+	return clipped
+}
+
+// Describe 暂停原因的中文描述
+func Describe(kind string) string {
+	switch kind {
+	case models.GapIdle:
+		return "离开（键鼠无操作）"
+	case models.GapPrivacy:
+		return "隐私规则暂停"
+	case models.GapPrivate:
+		return "私密模式（主动暂停）"
+	default:
+		return kind
+	}
+}
+
+// FormatList 将暂停时间段格式化为 Markdown 列表，每行形如 "- 10:05-10:30 离开（键鼠无操作），25 分钟"
+func FormatList(gaps []*models.CaptureGap) string {
+	lines := make([]string, 0, len(gaps))
+	for _, g := range gaps {
+		minutes := int(g.EndTime.Sub(g.StartTime).Round(time.Minute).Minutes())
+		lines = append(lines, fmt.Sprintf("- %s-%s %s，%d 分钟",
+			g.StartTime.Format("15:04"), g.EndTime.Format("15:04"), Describe(g.Kind), minutes))
+	}
+	return strings.Join(lines, "\n")
+}
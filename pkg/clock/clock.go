@@ -0,0 +1,81 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock 时间来源，模拟运行时替换为可手动推进的时钟
+type Clock interface {
+	Now() time.Time
+}
+
+// realClock 系统时钟
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+var (
+	mu      sync.RWMutex
+	current Clock = realClock{}
+)
+
+// Now 返回当前时钟的时间，业务代码应使用它代替 time.Now()
+// 仅用于耗时统计（如 LLM 调用延迟）时仍使用 time.Now()/time.Since()
+func Now() time.Time {
+	mu.RLock()
+	c := current
+	mu.RUnlock()
+	return c.Now()
+}
+
+// Set 设置全局时钟，传入 nil 恢复为系统时钟
+func Set(c Clock) {
+	mu.Lock()
+	defer mu.Unlock()
+	if c == nil {
+		c = realClock{}
+	}
+	current = c
+}
+
+// IsReal 判断当前是否使用系统时钟
+func IsReal() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	_, ok := current.(realClock)
+	return ok
+}
+
+// Fake 手动推进的时钟（模拟运行使用）
+type Fake struct {
+	mu  sync.RWMutex
+	now time.Time
+}
+
+// NewFake 创建从指定时间开始的时钟
+func NewFake(start time.Time) *Fake {
+	return &Fake{now: start}
+}
+
+// Now 返回时钟当前时间
+func (f *Fake) Now() time.Time {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.now
+}
+
+// Set 将时钟设置到指定时间
+func (f *Fake) Set(t time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = t
+}
+
+// Advance 将时钟向前推进 d
+func (f *Fake) Advance(d time.Duration) time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+	return f.now
+}
@@ -0,0 +1,74 @@
+package imagediff
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+
+	_ "github.com/gen2brain/webp"
+)
+
+// 指纹边长（图片缩小为 16x16 灰度）
+const size = 16
+
+// 每个格子内采样的点数（每边）
+const samples = 4
+
+// Fingerprint 图片缩小后的灰度像素，用于快速比较画面变化
+type Fingerprint [size * size]uint8
+
+// Compute 计算图片指纹
+func Compute(img image.Image) Fingerprint {
+	var fp Fingerprint
+	b := img.Bounds()
+	if b.Empty() {
+		return fp
+	}
+
+	for cy := 0; cy < size; cy++ {
+		for cx := 0; cx < size; cx++ {
+			// 在格子内均匀取点求平均灰度，避免逐像素遍历大图
+			total := 0
+			for sy := 0; sy < samples; sy++ {
+				for sx := 0; sx < samples; sx++ {
+					x := b.Min.X + (cx*samples+sx)*b.Dx()/(size*samples)
+					y := b.Min.Y + (cy*samples+sy)*b.Dy()/(size*samples)
+					total += int(color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y)
+				}
+			}
+			fp[cy*size+cx] = uint8(total / (samples * samples))
+		}
+	}
+	return fp
+}
+
+// Load 读取图片文件并计算指纹
+func Load(path string) (Fingerprint, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Fingerprint{}, fmt.Errorf("failed to open image: %w", err)
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return Fingerprint{}, fmt.Errorf("failed to decode image: %w", err)
+	}
+	return Compute(img), nil
+}
+
+// Difference 两个指纹的差异程度 (0-1)，0 表示画面相同
+func Difference(a, b Fingerprint) float64 {
+	total := 0
+	for i := range a {
+		d := int(a[i]) - int(b[i])
+		if d < 0 {
+			d = -d
+		}
+		total += d
+	}
+	return float64(total) / float64(len(a)*255)
+}
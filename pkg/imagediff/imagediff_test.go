@@ -0,0 +1,80 @@
+package imagediff
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fill 返回 w x h 的纯色图片
+func fill(w, h int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: c}, image.Point{}, draw.Src)
+	return img
+}
+
+// leftHalf 将图片左半边涂成指定颜色
+func leftHalf(img *image.RGBA, c color.Color) *image.RGBA {
+	b := img.Bounds()
+	draw.Draw(img, image.Rect(b.Min.X, b.Min.Y, b.Min.X+b.Dx()/2, b.Max.Y), &image.Uniform{C: c}, image.Point{}, draw.Src)
+	return img
+}
+
+func TestDifference(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b image.Image
+		want float64
+	}{
+		{"相同画面", fill(640, 480, color.White), fill(640, 480, color.White), 0},
+		{"黑白完全不同", fill(640, 480, color.Black), fill(640, 480, color.White), 1},
+		{"半边变化", fill(640, 480, color.White), leftHalf(fill(640, 480, color.White), color.Black), 0.5},
+		{"尺寸不同但内容相同", fill(1920, 1080, color.White), fill(320, 180, color.White), 0},
+		{"灰度按亮度计算", fill(64, 64, color.Gray{Y: 51}), fill(64, 64, color.Black), 0.2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Difference(Compute(tt.a), Compute(tt.b))
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Difference = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeEmptyImage(t *testing.T) {
+	if fp := Compute(image.NewRGBA(image.Rectangle{})); fp != (Fingerprint{}) {
+		t.Errorf("Compute(empty) = %v, want zero fingerprint", fp)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shot.png")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img := leftHalf(fill(200, 100, color.White), color.Black)
+	if err := png.Encode(f, img); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	fp, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if fp != Compute(img) {
+		t.Error("Load fingerprint differs from Compute on the same image")
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.png")); err == nil {
+		t.Error("Load of a missing file returned no error")
+	}
+}
@@ -0,0 +1,150 @@
+package imageformat
+
+import (
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gen2brain/webp"
+)
+
+// 内置格式名称（与 CaptureConfig.ImageFormat 及 screenshots.format 一致）
+const (
+	JPEG         = "jpeg"
+	PNG          = "png"
+	WebP         = "webp"
+	WebPLossless = "webp_lossless"
+)
+
+// Encoder 截图编码器
+type Encoder interface {
+	// Name 格式名称，写入配置与截图记录
+	Name() string
+	// Extension 文件扩展名（含点）
+	Extension() string
+	// MIMEType 发送给模型时使用的 MIME 类型
+	MIMEType() string
+	// Encode 编码图片，quality 为 1-100，无损格式忽略该参数
+	Encode(w io.Writer, img image.Image, quality int) error
+}
+
+var (
+	mu       sync.RWMutex
+	encoders = map[string]Encoder{}
+)
+
+func init() {
+	Register(jpegEncoder{})
+	Register(pngEncoder{})
+	Register(webpEncoder{})
+	Register(webpEncoder{lossless: true})
+}
+
+// Register 注册编码器，同名编码器会被替换
+func Register(e Encoder) {
+	mu.Lock()
+	defer mu.Unlock()
+	encoders[e.Name()] = e
+}
+
+// Lookup 按名称查找编码器
+func Lookup(name string) (Encoder, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	e, ok := encoders[strings.ToLower(name)]
+	return e, ok
+}
+
+// Default 默认编码器（JPEG）
+func Default() Encoder {
+	e, _ := Lookup(JPEG)
+	return e
+}
+
+// Names 已注册的格式名称（按字母排序）
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(encoders))
+	for name := range encoders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FromPath 根据文件扩展名推断格式，无法识别时返回空字符串
+func FromPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jpg", ".jpeg":
+		return JPEG
+	case ".png":
+		return PNG
+	case ".webp":
+		return WebP
+	}
+	return ""
+}
+
+// MIMEType 返回截图的 MIME 类型
+// 优先使用记录的格式，旧记录没有格式时按扩展名推断，均失败时按 JPEG 处理
+func MIMEType(format, path string) string {
+	if e, ok := Lookup(format); ok {
+		return e.MIMEType()
+	}
+	if e, ok := Lookup(FromPath(path)); ok {
+		return e.MIMEType()
+	}
+	return Default().MIMEType()
+}
+
+// jpegEncoder JPEG 有损编码
+type jpegEncoder struct{}
+
+func (jpegEncoder) Name() string      { return JPEG }
+func (jpegEncoder) Extension() string { return ".jpg" }
+func (jpegEncoder) MIMEType() string  { return "image/jpeg" }
+
+func (jpegEncoder) Encode(w io.Writer, img image.Image, quality int) error {
+	return jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+}
+
+// pngEncoder PNG 无损编码（文字清晰，但体积较大）
+type pngEncoder struct{}
+
+func (pngEncoder) Name() string      { return PNG }
+func (pngEncoder) Extension() string { return ".png" }
+func (pngEncoder) MIMEType() string  { return "image/png" }
+
+func (pngEncoder) Encode(w io.Writer, img image.Image, quality int) error {
+	enc := png.Encoder{CompressionLevel: png.BestCompression}
+	return enc.Encode(w, img)
+}
+
+// webpEncoder WebP 编码，支持有损与无损两种模式
+type webpEncoder struct {
+	lossless bool
+}
+
+func (e webpEncoder) Name() string {
+	if e.lossless {
+		return WebPLossless
+	}
+	return WebP
+}
+
+func (webpEncoder) Extension() string { return ".webp" }
+func (webpEncoder) MIMEType() string  { return "image/webp" }
+
+func (e webpEncoder) Encode(w io.Writer, img image.Image, quality int) error {
+	return webp.Encode(w, img, webp.Options{
+		Quality:  quality,
+		Lossless: e.lossless,
+		Method:   4,
+	})
+}
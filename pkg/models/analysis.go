@@ -0,0 +1,108 @@
+package models
+
+import "time"
+
+// 分析记录的解析状态
+const (
+	ParseStatusOK          = "ok"           // 调用成功且解析成功
+	ParseStatusParseFailed = "parse_failed" // 调用成功但响应无法解析
+	ParseStatusCallFailed  = "call_failed"  // 调用 LLM 失败
+)
+
+// AnalysisRun 一次 AI 分析的完整记录（用于审计与重放）
+type AnalysisRun struct {
+	ID            int64     `json:"id" db:"id"`
+	StartTime     time.Time `json:"start_time" db:"start_time"`
+	EndTime       time.Time `json:"end_time" db:"end_time"`
+	Provider      string    `json:"provider" db:"provider"`
+	Model         string    `json:"model" db:"model"`
+	MaxTokens     int       `json:"max_tokens" db:"max_tokens"`
+	Temperature   float32   `json:"temperature" db:"temperature"`
+	Prompt        string    `json:"prompt" db:"prompt"`
+	ScreenshotIDs []int64   `json:"screenshot_ids" db:"-"`
+	RawResponse   string    `json:"raw_response" db:"raw_response"`
+	ParseStatus   string    `json:"parse_status" db:"parse_status"`
+	Error         string    `json:"error,omitempty" db:"error"`
+	SummaryID     int64     `json:"summary_id,omitempty" db:"summary_id"`
+	ReplayOf      int64     `json:"replay_of,omitempty" db:"replay_of"`
+	LatencyMs     int64     `json:"latency_ms" db:"latency_ms"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// 评测任务状态
+const (
+	EvalStatusRunning = "running"
+	EvalStatusDone    = "done"
+	EvalStatusFailed  = "failed"
+)
+
+// EvalPeriod 评测使用的历史时间段
+type EvalPeriod struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// EvalCandidate 参与评测的提供商/模型/提示词组合
+type EvalCandidate struct {
+	Label    string `json:"label"`              // 显示名称，为空时使用 provider/model
+	Provider string `json:"provider"`           // 为空时使用当前配置
+	Model    string `json:"model"`              // 为空时使用当前配置
+	Endpoint string `json:"endpoint,omitempty"` // 自定义端点，为空时使用当前配置
+	APIKey   string `json:"api_key,omitempty"`  // 密钥，为空时使用当前配置（不会保存到数据库）
+	Prompt   string `json:"prompt,omitempty"`   // 提示词模板，支持 {start}/{end} 占位符，为空时使用默认提示词
+}
+
+// EvalRun 一次评测任务
+type EvalRun struct {
+	ID         int64           `json:"id" db:"id"`
+	Name       string          `json:"name" db:"name"`
+	Periods    []EvalPeriod    `json:"periods" db:"-"`
+	Candidates []EvalCandidate `json:"candidates" db:"-"`
+	Status     string          `json:"status" db:"status"`
+	Error      string          `json:"error,omitempty" db:"error"`
+	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
+	FinishedAt *time.Time      `json:"finished_at,omitempty" db:"finished_at"`
+	Results    []*EvalResult   `json:"results,omitempty" db:"-"`
+}
+
+// EvalResult 单个候选在单个时间段上的评测输出
+type EvalResult struct {
+	ID                int64     `json:"id" db:"id"`
+	EvalRunID         int64     `json:"eval_run_id" db:"eval_run_id"`
+	PeriodStart       time.Time `json:"period_start" db:"period_start"`
+	PeriodEnd         time.Time `json:"period_end" db:"period_end"`
+	Candidate         string    `json:"candidate" db:"candidate"`
+	Provider          string    `json:"provider" db:"provider"`
+	Model             string    `json:"model" db:"model"`
+	Prompt            string    `json:"prompt" db:"prompt"`
+	ScreenshotIDs     []int64   `json:"screenshot_ids" db:"-"`
+	RawResponse       string    `json:"raw_response" db:"raw_response"`
+	Summary           string    `json:"summary" db:"summary"`
+	JSONValid         bool      `json:"json_valid" db:"json_valid"`
+	DurationPlausible bool      `json:"duration_plausible" db:"duration_plausible"`
+	TotalMinutes      int       `json:"total_minutes" db:"total_minutes"`
+	LatencyMs         int64     `json:"latency_ms" db:"latency_ms"`
+	Error             string    `json:"error,omitempty" db:"error"`
+	CreatedAt         time.Time `json:"created_at" db:"created_at"`
+}
+
+// 待补分析时间段的状态
+const (
+	BacklogStatusPending = "pending" // 等待重试
+	BacklogStatusDone    = "done"    // 已完成分析
+	BacklogStatusFailed  = "failed"  // 超过最大重试次数，放弃
+)
+
+// BacklogItem 因 LLM 不可用等原因未能完成分析、等待补分析的时间段
+type BacklogItem struct {
+	ID            int64     `json:"id" db:"id"`
+	StartTime     time.Time `json:"start_time" db:"start_time"`
+	EndTime       time.Time `json:"end_time" db:"end_time"`
+	Status        string    `json:"status" db:"status"`
+	Reason        string    `json:"reason" db:"reason"`
+	Attempts      int       `json:"attempts" db:"attempts"`
+	LastError     string    `json:"last_error,omitempty" db:"last_error"`
+	NextAttemptAt time.Time `json:"next_attempt_at" db:"next_attempt_at"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// AppUsageReport 按前台窗口采样实测的应用使用时长
+type AppUsageReport struct {
+	Start        time.Time       `json:"start"`
+	End          time.Time       `json:"end"`
+	TotalSeconds int64           `json:"total_seconds"` // 有窗口记录的总时长
+	Samples      int             `json:"samples"`       // 参与统计的采样次数
+	Source       string          `json:"source"`        // 数据来源: "samples"（窗口采样）或 "segments"（分析时保存的时间段统计）
+	Apps         []AppUsageEntry `json:"apps"`          // 按时长降序
+}
+
+// AppUsageEntry 单个应用的使用时长
+type AppUsageEntry struct {
+	App     string            `json:"app"` // 应用名（进程名去掉 .exe 后缀）
+	Seconds int64             `json:"seconds"`
+	Titles  []TitleUsageEntry `json:"titles"` // 按时长降序
+}
+
+// TitleUsageEntry 单个窗口标题的使用时长
+type TitleUsageEntry struct {
+	Title   string `json:"title"`
+	Seconds int64  `json:"seconds"`
+}
+
+// SegmentAppUsage 分析时间段内的实测使用时长（每个应用 + 窗口标题一条）
+type SegmentAppUsage struct {
+	ID        int64     `json:"id" db:"id"`
+	SummaryID int64     `json:"summary_id" db:"summary_id"`
+	StartTime time.Time `json:"start_time" db:"start_time"`
+	EndTime   time.Time `json:"end_time" db:"end_time"`
+	App       string    `json:"app" db:"app"`
+	Title     string    `json:"title" db:"title"`
+	Seconds   int64     `json:"seconds" db:"seconds"`
+}
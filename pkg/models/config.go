@@ -16,6 +16,9 @@ type AppConfig struct {
 
 	// 服务器配置
 	Server ServerConfig `json:"server"`
+
+	// 隐私配置
+	Privacy PrivacyConfig `json:"privacy"`
 }
 
 // CaptureConfig 截屏配置
@@ -25,10 +28,20 @@ type CaptureConfig struct {
 	Quality         int    `json:"quality"`           // 图片质量 (1-100)
 	Enabled         bool   `json:"enabled"`           // 是否启用截屏
 	MergeScreens    bool   `json:"merge_screens"`     // 是否拼接多屏幕为一张图片
-	ImageFormat     string `json:"image_format"`      // 图片格式: "jpeg" 或 "webp"
+	ImageFormat     string `json:"image_format"`      // 图片格式: "jpeg"、"png"、"webp" 或 "webp_lossless"
 	MaxWidth        int    `json:"max_width"`         // 最大宽度（0表示不限制）
 	MaxHeight       int    `json:"max_height"`        // 最大高度（0表示不限制）
 	EnableResize    bool   `json:"enable_resize"`     // 是否启用智能缩放
+
+	SkipUnchanged      bool    `json:"skip_unchanged"`      // 画面无变化时不写入新文件，只记录心跳
+	UnchangedThreshold float64 `json:"unchanged_threshold"` // 判定为无变化的画面差异上限 (0-1)
+	UnchangedMaxAge    int     `json:"unchanged_max_age"`   // 连续无变化时强制保存完整截图的间隔（秒，0表示不强制）
+
+	AdaptiveInterval bool    `json:"adaptive_interval"` // 画面静止时逐步拉长截屏间隔，Interval 为最小（基础）间隔
+	MaxInterval      int     `json:"max_interval"`      // 自适应截屏间隔上限（秒）
+	IntervalGrowth   float64 `json:"interval_growth"`   // 每轮画面无变化时间隔的增长倍数
+
+	IdleThreshold int `json:"idle_threshold"` // 键鼠无操作超过该时长（秒）后暂停截屏并记录空闲时段，0 表示不检测
 }
 
 // WorkSchedule 工作时间配置
@@ -38,6 +51,30 @@ type WorkSchedule struct {
 	WorkDays         []int    `json:"work_days"`         // 工作日 (0=周日, 1=周一, ...)
 	AnalysisInterval int      `json:"analysis_interval"` // AI 分析间隔（分钟）
 	Enabled          bool     `json:"enabled"`           // 是否启用时间限制
+	CatchUpLookback  int      `json:"catch_up_lookback"` // 启动或唤醒时补分析的回溯时长（小时），0 表示不补分析
+	SegmentMinutes   int      `json:"segment_minutes"`   // 每个分析时间段的长度（分钟）
+	SegmentAlignment string   `json:"segment_alignment"` // 时间段对齐方式: "clock" 按整点对齐, "work_start" 按工作开始时间对齐
+
+	Windows        []TimeWindow         `json:"windows"`         // 每天的工作时段（如上午、下午），为空时使用 StartTime-EndTime
+	WeekdayWindows map[int][]TimeWindow `json:"weekday_windows"` // 按星期几覆盖工作时段 (0=周日, 1=周一, ...)，空列表表示当天不工作
+	DayBoundary    string               `json:"day_boundary"`    // 逻辑日分界时间，如 "06:00" 表示凌晨 6 点前算作前一天（夜班）
+
+	Triggers AnalysisTriggers `json:"triggers"` // 事件触发分析（与定时分析同时生效）
+}
+
+// AnalysisTriggers 事件触发分析配置，各触发条件为 0 表示不启用
+// 触发后分析当前时间段中尚未分析的部分，与定时分析共用同一时间段的去重
+type AnalysisTriggers struct {
+	ScreenshotCount int     `json:"screenshot_count"` // 新增截图达到 N 张后分析
+	IdleMinutes     int     `json:"idle_minutes"`     // 屏幕无活动（没有新截图）超过 X 分钟后分析
+	ActivityChange  float64 `json:"activity_change"`  // 画面变化程度超过该阈值 (0-1) 时分析
+	MinInterval     int     `json:"min_interval"`     // 两次事件触发分析的最小间隔（分钟）
+}
+
+// TimeWindow 一段工作时间 "09:00" - "12:00"，结束时间早于开始时间表示跨越午夜（如 "22:00" - "06:00"）
+type TimeWindow struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
 }
 
 // AIConfig AI 配置
@@ -50,6 +87,11 @@ type AIConfig struct {
 	MaxTokens    int     `json:"max_tokens"`    // 最大 token 数
 	Temperature  float32 `json:"temperature"`   // 温度参数
 	MaxImages    int     `json:"max_images"`    // 单次分析最大图片数
+
+	FewShotEnabled  bool `json:"few_shot_enabled"`  // 是否将好评总结作为示例加入提示词
+	FewShotExamples int  `json:"few_shot_examples"` // 提示词中最多包含的示例数
+
+	CaptionMode bool `json:"caption_mode"` // 是否为每张采样截图生成并保存单独的描述
 }
 
 // StorageConfig 存储配置
@@ -69,6 +111,57 @@ type ServerConfig struct {
 	AutoOpenBrowser bool `json:"auto_open_browser"` // 启动时自动打开浏览器
 }
 
+// PrivacyConfig 隐私配置
+type PrivacyConfig struct {
+	Rules []PrivacyRule `json:"rules"` // 敏感应用规则，前台窗口匹配任一规则时不截屏、涂黑或遮挡该窗口
+
+	Redactions     []RedactionRegion `json:"redactions"`      // 发送给 AI 前固定遮挡的区域（如聊天侧栏），本地截图保持原样
+	RedactionStyle string            `json:"redaction_style"` // 遮挡方式: "fill" 纯色填充（默认）, "blur" 模糊
+}
+
+// PrivacyRule 敏感应用规则，每条规则按一种方式匹配前台窗口
+type PrivacyRule struct {
+	Name    string `json:"name"`    // 规则名称，作为跳过截屏的原因记录
+	Match   string `json:"match"`   // 匹配方式: "process"、"title" 或 "url"
+	Pattern string `json:"pattern"` // 进程名（不区分大小写，可省略 .exe）、标题正则表达式或网址/域名
+	Action  string `json:"action"`  // 匹配后的处理: "skip" 跳过截屏（默认）, "blackout" 涂黑窗口区域, "redact" 发送给 AI 前遮挡窗口区域
+}
+
+// RedactionRegion 固定遮挡区域
+type RedactionRegion struct {
+	Name    string `json:"name"`
+	Screens []int  `json:"screens"` // 适用的屏幕索引（拼接截图为 -1），为空表示所有截图
+	RelativeRect
+}
+
+// RelativeRect 相对截图宽高的区域，各值为 0-1 的比例，不受缩放影响
+type RelativeRect struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// 隐私规则匹配方式
+const (
+	PrivacyMatchProcess = "process"
+	PrivacyMatchTitle   = "title"
+	PrivacyMatchURL     = "url"
+)
+
+// 隐私规则处理方式
+const (
+	PrivacySkip     = "skip"
+	PrivacyBlackout = "blackout"
+	PrivacyRedact   = "redact"
+)
+
+// 遮挡方式
+const (
+	RedactFill = "fill"
+	RedactBlur = "blur"
+)
+
 // DefaultConfig 返回默认配置
 func DefaultConfig() *AppConfig {
 	return &AppConfig{
@@ -82,6 +175,16 @@ func DefaultConfig() *AppConfig {
 			MaxWidth:        0,      // 0 表示不限制
 			MaxHeight:       0,      // 0 表示不限制
 			EnableResize:    false,  // 默认不启用智能缩放
+
+			SkipUnchanged:      false, // 默认每次都写入截图文件，由用户开启
+			UnchangedThreshold: 0.005,
+			UnchangedMaxAge:    300,
+
+			AdaptiveInterval: false,
+			MaxInterval:      60,
+			IntervalGrowth:   1.5,
+
+			IdleThreshold: 0, // 默认不检测离开，由用户开启
 		},
 		Schedule: WorkSchedule{
 			StartTime:        "09:00",
@@ -89,6 +192,13 @@ func DefaultConfig() *AppConfig {
 			WorkDays:         []int{1, 2, 3, 4, 5}, // 周一到周五
 			AnalysisInterval: 60,
 			Enabled:          true,
+			CatchUpLookback:  24,
+			SegmentMinutes:   60,
+			SegmentAlignment: "clock",
+			DayBoundary:      "00:00",
+			Triggers: AnalysisTriggers{
+				MinInterval: 10,
+			},
 		},
 		AI: AIConfig{
 			Provider:    "openai",
@@ -96,6 +206,11 @@ func DefaultConfig() *AppConfig {
 			MaxTokens:   2000,
 			Temperature: 0.3,
 			MaxImages:   20,
+
+			FewShotEnabled:  false,
+			FewShotExamples: 3,
+
+			CaptionMode: false,
 		},
 		Storage: StorageConfig{
 			DataDir:         "./data",
@@ -110,5 +225,14 @@ func DefaultConfig() *AppConfig {
 			EnableCORS:      true,
 			AutoOpenBrowser: true,
 		},
+		Privacy: PrivacyConfig{
+			Rules: []PrivacyRule{
+				{Name: "KeePass", Match: PrivacyMatchProcess, Pattern: "KeePass", Action: PrivacySkip},
+				{Name: "KeePassXC", Match: PrivacyMatchProcess, Pattern: "KeePassXC", Action: PrivacySkip},
+				{Name: "1Password", Match: PrivacyMatchProcess, Pattern: "1Password", Action: PrivacySkip},
+				{Name: "Bitwarden", Match: PrivacyMatchProcess, Pattern: "Bitwarden", Action: PrivacySkip},
+			},
+			RedactionStyle: RedactFill,
+		},
 	}
 }
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// 总结评分
+const (
+	RatingUp   = 1  // 👍
+	RatingDown = -1 // 👎
+)
+
+// SummaryFeedback 用户对工作总结的评分与修正
+type SummaryFeedback struct {
+	ID               int64     `json:"id" db:"id"`
+	SummaryID        int64     `json:"summary_id" db:"summary_id"`
+	Rating           int       `json:"rating" db:"rating"`
+	CorrectedSummary string    `json:"corrected_summary,omitempty" db:"corrected_summary"`
+	Provider         string    `json:"provider" db:"provider"`
+	Model            string    `json:"model" db:"model"`
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// FeedbackTrend 按日期与模型聚合的评分统计
+type FeedbackTrend struct {
+	Date      string  `json:"date"`
+	Provider  string  `json:"provider"`
+	Model     string  `json:"model"`
+	Up        int     `json:"up"`
+	Down      int     `json:"down"`
+	Corrected int     `json:"corrected"`
+	Score     float64 `json:"score"` // 好评率 up / (up + down)
+}
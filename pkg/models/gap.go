@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// 截屏暂停原因
+const (
+	GapIdle    = "idle"    // 键鼠长时间无操作（离开座位）
+	GapPrivacy = "privacy" // 前台窗口匹配隐私规则
+	GapPrivate = "private" // 用户主动开启私密模式
+)
+
+// CaptureGap 截屏暂停的时间段，用于在时间线和报告中区分休息与数据缺失
+type CaptureGap struct {
+	ID        int64     `json:"id" db:"id"`
+	Kind      string    `json:"kind" db:"kind"`               // 暂停原因，见 Gap* 常量
+	StartTime time.Time `json:"start_time" db:"start_time"`   // 开始时间（空闲时为最后一次键鼠操作的时间）
+	EndTime   time.Time `json:"end_time" db:"end_time"`       // 结束时间，暂停尚未结束时为最近一次检测的时间（私密模式为计划结束时间）
+	Open      bool      `json:"open" db:"open"`               // 暂停是否仍在进行中
+	Reason    string    `json:"reason,omitempty" db:"reason"` // 暂停的具体原因（如隐私规则名称），不包含窗口内容
+	Frames    int       `json:"frames,omitempty" db:"frames"` // 期间跳过的截屏次数
+}
+
+// CaptureGapReport 时间范围内的截屏暂停记录
+type CaptureGapReport struct {
+	Start        time.Time        `json:"start"`
+	End          time.Time        `json:"end"`
+	Gaps         []*CaptureGap    `json:"gaps"`          // 按开始时间排序，已截断到查询范围内
+	TotalSeconds map[string]int64 `json:"total_seconds"` // 按暂停原因汇总的时长
+}
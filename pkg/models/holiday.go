@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// 节假日日历条目类型
+const (
+	HolidayKindOff     = "holiday" // 放假（即使是工作日也不工作）
+	HolidayKindWorkday = "workday" // 调休上班（即使是周末也工作）
+)
+
+// Holiday 节假日日历条目
+type Holiday struct {
+	Date      string    `json:"date" db:"date"` // "2006-01-02"
+	Name      string    `json:"name" db:"name"`
+	Kind      string    `json:"kind" db:"kind"`
+	Source    string    `json:"source" db:"source"` // manual, ics, json
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
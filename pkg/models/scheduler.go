@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// 调度任务的触发方式
+const (
+	JobTriggerSchedule = "schedule" // 定时触发
+	JobTriggerManual   = "manual"   // 通过 API 手动触发
+	JobTriggerStartup  = "startup"  // 调度器启动时触发
+)
+
+// 调度任务单次执行的结果
+const (
+	JobRunSuccess = "success" // 执行完成
+	JobRunFailed  = "failed"  // 执行出错
+	JobRunSkipped = "skipped" // 条件不满足（如不在工作时间）或没有需要处理的内容
+)
+
+// JobInfo 调度任务的当前状态
+type JobInfo struct {
+	Name         string     `json:"name"`
+	Description  string     `json:"description"`
+	Specs        []string   `json:"specs"` // cron 表达式，为空表示仅手动触发
+	Paused       bool       `json:"paused"`
+	Running      bool       `json:"running"`
+	NextRun      *time.Time `json:"next_run,omitempty"`
+	PrevRun      *time.Time `json:"prev_run,omitempty"`
+	LastResult   string     `json:"last_result,omitempty"`
+	LastError    string     `json:"last_error,omitempty"`
+	LastDuration int64      `json:"last_duration_ms"`
+}
+
+// JobRun 调度任务的一次执行记录
+type JobRun struct {
+	ID         int64     `json:"id" db:"id"`
+	JobName    string    `json:"job_name" db:"job_name"`
+	Trigger    string    `json:"trigger" db:"trigger"`
+	StartedAt  time.Time `json:"started_at" db:"started_at"`
+	DurationMs int64     `json:"duration_ms" db:"duration_ms"`
+	Status     string    `json:"status" db:"status"` // 执行结果，见 JobRun* 常量
+	Result     string    `json:"result" db:"result"`
+	Error      string    `json:"error,omitempty" db:"error"`
+}
@@ -10,10 +10,36 @@ type Screenshot struct {
 	FilePath    string    `json:"file_path" db:"file_path"`
 	FileSize    int64     `json:"file_size" db:"file_size"`
 	Resolution  string    `json:"resolution" db:"resolution"`
+	Format      string    `json:"format" db:"format"` // 图片格式: jpeg/png/webp/webp_lossless，旧记录为空
 	Analyzed    bool      `json:"analyzed" db:"analyzed"`
+	Unchanged   bool      `json:"unchanged" db:"unchanged"`           // 画面无变化的心跳记录，FilePath 指向 SourceID 的文件
+	SourceID    int64     `json:"source_id,omitempty" db:"source_id"` // 心跳记录对应的原始截图 ID
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 }
 
+// ScreenshotWindow 截图时的前台窗口信息
+type ScreenshotWindow struct {
+	ID           int64        `json:"id" db:"id"`
+	ScreenshotID int64        `json:"screenshot_id" db:"screenshot_id"`
+	Timestamp    time.Time    `json:"timestamp" db:"-"` // 截图时间，查询时关联得到
+	Title        string       `json:"title" db:"title"`
+	ProcessName  string       `json:"process_name" db:"process_name"`
+	PID          int          `json:"pid" db:"pid"`
+	Region       RelativeRect `json:"region" db:"-"` // 窗口在截图中的相对区域（region_x/y/w/h 列），未知时为零值
+}
+
+// ScreenshotCaption 单张截图的 AI 描述
+type ScreenshotCaption struct {
+	ID            int64     `json:"id" db:"id"`
+	ScreenshotID  int64     `json:"screenshot_id" db:"screenshot_id"`
+	Timestamp     time.Time `json:"timestamp" db:"-"` // 截图时间（来自 screenshots 表）
+	Caption       string    `json:"caption" db:"caption"`
+	Provider      string    `json:"provider" db:"provider"`
+	Model         string    `json:"model" db:"model"`
+	AnalysisRunID int64     `json:"analysis_run_id,omitempty" db:"analysis_run_id"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
 // WorkSummary 工作总结
 type WorkSummary struct {
 	ID         int64      `json:"id" db:"id"`
@@ -54,8 +80,13 @@ type StorageStats struct {
 type ServiceStatus struct {
 	Running         bool      `json:"running"`
 	CaptureEnabled  bool      `json:"capture_enabled"`
+	CaptureInterval float64   `json:"capture_interval"`        // 当前实际截屏间隔（秒），自适应时会随画面活跃度变化
+	Idle            bool      `json:"idle"`                    // 是否因键鼠长时间无操作而暂停截屏
+	Private         bool      `json:"private"`                 // 是否处于私密模式
+	PrivateUntil    time.Time `json:"private_until,omitempty"` // 私密模式自动结束的时间
 	LastCapture     time.Time `json:"last_capture,omitempty"`
 	LastAnalysis    time.Time `json:"last_analysis,omitempty"`
 	TodayCaptures   int       `json:"today_captures"`
 	TodaySummaries  int       `json:"today_summaries"`
+	AnalysisBacklog int       `json:"analysis_backlog"` // 等待补分析的时间段数量
 }
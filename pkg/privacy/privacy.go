@@ -0,0 +1,148 @@
+package privacy
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"WorkTrackerAI/pkg/activewindow"
+	"WorkTrackerAI/pkg/models"
+)
+
+// urlPattern 在窗口标题中查找网址或域名（浏览器通常只在标题中显示页面标题，
+// 需要安装在标题中显示网址的扩展，或网站本身把域名放在标题里）
+var urlPattern = regexp.MustCompile(`(?i)(?:https?://)?((?:[a-z0-9-]+\.)+[a-z]{2,})(?::\d+)?(/[^\s]*)?`)
+
+// Matcher 编译后的隐私规则
+type Matcher struct {
+	rules []rule
+}
+
+type rule struct {
+	models.PrivacyRule
+	process string         // 小写且去掉 .exe 的进程名
+	title   *regexp.Regexp // 标题正则
+	host    string         // 小写域名
+	path    string         // 网址路径前缀
+}
+
+// Compile 编译隐私规则，无效的规则被忽略并在返回的错误中列出
+func Compile(rules []models.PrivacyRule) (*Matcher, error) {
+	m := &Matcher{}
+	var errs []error
+	for i, r := range rules {
+		if r.Action == "" {
+			r.Action = models.PrivacySkip
+		}
+		if r.Name == "" {
+			r.Name = fmt.Sprintf("规则%d", i+1)
+		}
+
+		compiled, err := compileRule(r)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("隐私规则「%s」无效: %w", r.Name, err))
+			continue
+		}
+		m.rules = append(m.rules, compiled)
+	}
+	return m, errors.Join(errs...)
+}
+
+func compileRule(r models.PrivacyRule) (rule, error) {
+	c := rule{PrivacyRule: r}
+	if r.Action != models.PrivacySkip && r.Action != models.PrivacyBlackout && r.Action != models.PrivacyRedact {
+		return c, fmt.Errorf("未知的处理方式 %q", r.Action)
+	}
+
+	pattern := strings.TrimSpace(r.Pattern)
+	if pattern == "" {
+		return c, fmt.Errorf("匹配内容不能为空")
+	}
+
+	switch r.Match {
+	case models.PrivacyMatchProcess:
+		c.process = normalizeProcess(pattern)
+	case models.PrivacyMatchTitle:
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return c, fmt.Errorf("标题正则表达式错误: %w", err)
+		}
+		c.title = re
+	case models.PrivacyMatchURL:
+		pattern = strings.ToLower(pattern)
+		pattern = strings.TrimPrefix(strings.TrimPrefix(pattern, "https://"), "http://")
+		pattern = strings.TrimPrefix(pattern, "*.")
+		c.host, c.path = pattern, ""
+		if i := strings.Index(pattern, "/"); i >= 0 {
+			c.host, c.path = pattern[:i], pattern[i:]
+		}
+		if c.host == "" {
+			return c, fmt.Errorf("网址缺少域名")
+		}
+	default:
+		return c, fmt.Errorf("未知的匹配方式 %q", r.Match)
+	}
+	return c, nil
+}
+
+// Validate 校验隐私配置
+func Validate(cfg models.PrivacyConfig) error {
+	if _, err := Compile(cfg.Rules); err != nil {
+		return err
+	}
+
+	if s := cfg.RedactionStyle; s != "" && s != models.RedactFill && s != models.RedactBlur {
+		return fmt.Errorf("未知的遮挡方式 %q", s)
+	}
+	for i, r := range cfg.Redactions {
+		if r.X < 0 || r.Y < 0 || r.Width <= 0 || r.Height <= 0 || r.X+r.Width > 1 || r.Y+r.Height > 1 {
+			return fmt.Errorf("遮挡区域 %d 超出截图范围（各值为 0-1 的比例）", i+1)
+		}
+	}
+	return nil
+}
+
+// Match 返回前台窗口匹配的第一条规则，没有匹配或窗口未知时返回 nil
+func (m *Matcher) Match(info *activewindow.Info) *models.PrivacyRule {
+	if m == nil || info == nil {
+		return nil
+	}
+	for i := range m.rules {
+		if m.rules[i].matches(info) {
+			r := m.rules[i].PrivacyRule
+			return &r
+		}
+	}
+	return nil
+}
+
+// Len 有效规则数量
+func (m *Matcher) Len() int {
+	if m == nil {
+		return 0
+	}
+	return len(m.rules)
+}
+
+func (r *rule) matches(info *activewindow.Info) bool {
+	switch {
+	case r.process != "":
+		return info.ProcessName != "" && normalizeProcess(info.ProcessName) == r.process
+	case r.title != nil:
+		return r.title.MatchString(info.Title)
+	case r.host != "":
+		for _, m := range urlPattern.FindAllStringSubmatch(info.Title, -1) {
+			host := strings.ToLower(m[1])
+			if (host == r.host || strings.HasSuffix(host, "."+r.host)) && strings.HasPrefix(strings.ToLower(m[2]), r.path) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// normalizeProcess 进程名转为小写并去掉 .exe 后缀
+func normalizeProcess(name string) string {
+	return strings.TrimSuffix(strings.ToLower(name), ".exe")
+}
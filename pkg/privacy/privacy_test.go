@@ -0,0 +1,148 @@
+package privacy
+
+import (
+	"testing"
+
+	"WorkTrackerAI/pkg/activewindow"
+	"WorkTrackerAI/pkg/models"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  models.PrivacyRule
+		info  *activewindow.Info
+		match bool
+	}{
+		{
+			name:  "进程名不区分大小写",
+			rule:  models.PrivacyRule{Match: models.PrivacyMatchProcess, Pattern: "KeePass"},
+			info:  &activewindow.Info{ProcessName: "keepass.exe"},
+			match: true,
+		},
+		{
+			name:  "规则中的 .exe 可省略",
+			rule:  models.PrivacyRule{Match: models.PrivacyMatchProcess, Pattern: "WeChat.exe"},
+			info:  &activewindow.Info{ProcessName: "wechat"},
+			match: true,
+		},
+		{
+			name:  "进程名需完全相同",
+			rule:  models.PrivacyRule{Match: models.PrivacyMatchProcess, Pattern: "code"},
+			info:  &activewindow.Info{ProcessName: "vscode.exe"},
+			match: false,
+		},
+		{
+			name:  "进程名未知时不匹配",
+			rule:  models.PrivacyRule{Match: models.PrivacyMatchProcess, Pattern: "code"},
+			info:  &activewindow.Info{Title: "code"},
+			match: false,
+		},
+		{
+			name:  "标题正则",
+			rule:  models.PrivacyRule{Match: models.PrivacyMatchTitle, Pattern: `(?i)incognito|无痕`},
+			info:  &activewindow.Info{Title: "新标签页 - Chrome (无痕模式)"},
+			match: true,
+		},
+		{
+			name:  "标题正则不匹配",
+			rule:  models.PrivacyRule{Match: models.PrivacyMatchTitle, Pattern: `^Bank`},
+			info:  &activewindow.Info{Title: "My Bank - Chrome"},
+			match: false,
+		},
+		{
+			name:  "网址匹配域名",
+			rule:  models.PrivacyRule{Match: models.PrivacyMatchURL, Pattern: "bank.com"},
+			info:  &activewindow.Info{Title: "Login - https://bank.com/login - Chrome"},
+			match: true,
+		},
+		{
+			name:  "网址匹配子域名",
+			rule:  models.PrivacyRule{Match: models.PrivacyMatchURL, Pattern: "*.bank.com"},
+			info:  &activewindow.Info{Title: "online.BANK.com - Chrome"},
+			match: true,
+		},
+		{
+			name:  "域名后缀需在点号处分隔",
+			rule:  models.PrivacyRule{Match: models.PrivacyMatchURL, Pattern: "bank.com"},
+			info:  &activewindow.Info{Title: "mybank.com - Chrome"},
+			match: false,
+		},
+		{
+			name:  "网址匹配路径前缀",
+			rule:  models.PrivacyRule{Match: models.PrivacyMatchURL, Pattern: "https://mail.example.com/u/1"},
+			info:  &activewindow.Info{Title: "Inbox - mail.example.com/u/1/inbox"},
+			match: true,
+		},
+		{
+			name:  "路径前缀不同",
+			rule:  models.PrivacyRule{Match: models.PrivacyMatchURL, Pattern: "mail.example.com/u/1"},
+			info:  &activewindow.Info{Title: "Inbox - mail.example.com/u/0/inbox"},
+			match: false,
+		},
+		{
+			name:  "窗口未知",
+			rule:  models.PrivacyRule{Match: models.PrivacyMatchTitle, Pattern: ".*"},
+			info:  nil,
+			match: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Compile([]models.PrivacyRule{tt.rule})
+			if err != nil {
+				t.Fatalf("Compile: %v", err)
+			}
+			if got := m.Match(tt.info); (got != nil) != tt.match {
+				t.Errorf("Match = %v, want match %v", got, tt.match)
+			}
+		})
+	}
+}
+
+func TestMatchFirstRule(t *testing.T) {
+	m, err := Compile([]models.PrivacyRule{
+		{Match: models.PrivacyMatchTitle, Pattern: "Bank", Action: models.PrivacyBlackout},
+		{Name: "浏览器", Match: models.PrivacyMatchProcess, Pattern: "chrome"},
+	})
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+
+	got := m.Match(&activewindow.Info{ProcessName: "chrome.exe", Title: "My Bank"})
+	if got == nil || got.Name != "规则1" || got.Action != models.PrivacyBlackout {
+		t.Errorf("Match = %+v, want the first rule with a default name", got)
+	}
+
+	got = m.Match(&activewindow.Info{ProcessName: "chrome.exe", Title: "News"})
+	if got == nil || got.Name != "浏览器" || got.Action != models.PrivacySkip {
+		t.Errorf("Match = %+v, want the second rule with the default action", got)
+	}
+}
+
+func TestCompileSkipsInvalidRules(t *testing.T) {
+	tests := []struct {
+		name string
+		rule models.PrivacyRule
+	}{
+		{"未知的匹配方式", models.PrivacyRule{Match: "window", Pattern: "x"}},
+		{"未知的处理方式", models.PrivacyRule{Match: models.PrivacyMatchProcess, Pattern: "x", Action: "delete"}},
+		{"匹配内容为空", models.PrivacyRule{Match: models.PrivacyMatchProcess, Pattern: "  "}},
+		{"标题正则错误", models.PrivacyRule{Match: models.PrivacyMatchTitle, Pattern: "("}},
+		{"网址缺少域名", models.PrivacyRule{Match: models.PrivacyMatchURL, Pattern: "https:///path"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid := models.PrivacyRule{Match: models.PrivacyMatchProcess, Pattern: "keepass"}
+			m, err := Compile([]models.PrivacyRule{tt.rule, valid})
+			if err == nil {
+				t.Error("Compile returned no error for an invalid rule")
+			}
+			if m.Len() != 1 {
+				t.Errorf("Len = %d, want only the valid rule", m.Len())
+			}
+		})
+	}
+}
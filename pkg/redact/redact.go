@@ -0,0 +1,108 @@
+package redact
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"WorkTrackerAI/pkg/models"
+
+	"github.com/nfnt/resize"
+)
+
+// fillColor 纯色遮挡使用的颜色（灰色，与锁屏黑屏和涂黑的敏感窗口区分）
+var fillColor = color.RGBA{R: 128, G: 128, B: 128, A: 255}
+
+// blurFactor 模糊时先缩小的倍数，越大越模糊
+const blurFactor = 24
+
+// Relative 将 area 换算为相对 bounds 的区域，两者不相交时返回零值
+func Relative(area, bounds image.Rectangle) models.RelativeRect {
+	r := area.Intersect(bounds)
+	if r.Empty() || bounds.Empty() {
+		return models.RelativeRect{}
+	}
+	w, h := float64(bounds.Dx()), float64(bounds.Dy())
+	return models.RelativeRect{
+		X:      float64(r.Min.X-bounds.Min.X) / w,
+		Y:      float64(r.Min.Y-bounds.Min.Y) / h,
+		Width:  float64(r.Dx()) / w,
+		Height: float64(r.Dy()) / h,
+	}
+}
+
+// Rect 将相对区域换算为 bounds 中的像素区域（向外取整，避免遮挡不全）
+func Rect(r models.RelativeRect, bounds image.Rectangle) image.Rectangle {
+	w, h := float64(bounds.Dx()), float64(bounds.Dy())
+	rect := image.Rect(
+		bounds.Min.X+int(r.X*w),
+		bounds.Min.Y+int(r.Y*h),
+		bounds.Min.X+int(math.Ceil((r.X+r.Width)*w)),
+		bounds.Min.Y+int(math.Ceil((r.Y+r.Height)*h)),
+	)
+	return rect.Intersect(bounds)
+}
+
+// Empty 区域是否为空
+func Empty(r models.RelativeRect) bool {
+	return r.Width <= 0 || r.Height <= 0
+}
+
+// Regions 返回适用于指定屏幕截图的固定遮挡区域
+func Regions(cfg models.PrivacyConfig, screenIndex int) []models.RelativeRect {
+	var regions []models.RelativeRect
+	for _, r := range cfg.Redactions {
+		if Empty(r.RelativeRect) || !appliesTo(r.Screens, screenIndex) {
+			continue
+		}
+		regions = append(regions, r.RelativeRect)
+	}
+	return regions
+}
+
+func appliesTo(screens []int, screenIndex int) bool {
+	if len(screens) == 0 {
+		return true
+	}
+	for _, s := range screens {
+		if s == screenIndex {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply 按遮挡方式在图片上遮挡各区域（直接修改 img）
+func Apply(img draw.Image, regions []models.RelativeRect, style string) {
+	for _, region := range regions {
+		r := Rect(region, img.Bounds())
+		if r.Empty() {
+			continue
+		}
+		if style == models.RedactBlur {
+			blur(img, r)
+		} else {
+			draw.Draw(img, r, image.NewUniform(fillColor), image.Point{}, draw.Src)
+		}
+	}
+}
+
+// blur 将区域缩小后再放大，使文字与细节无法辨认
+func blur(img draw.Image, r image.Rectangle) {
+	sub := image.NewRGBA(image.Rect(0, 0, r.Dx(), r.Dy()))
+	draw.Draw(sub, sub.Bounds(), img, r.Min, draw.Src)
+
+	w := uint(r.Dx()/blurFactor + 1)
+	h := uint(r.Dy()/blurFactor + 1)
+	small := resize.Resize(w, h, sub, resize.Bilinear)
+	blurred := resize.Resize(uint(r.Dx()), uint(r.Dy()), small, resize.Bilinear)
+	draw.Draw(img, r, blurred, blurred.Bounds().Min, draw.Src)
+}
+
+// ToRGBA 复制为可修改的 RGBA 图片
+func ToRGBA(img image.Image) *image.RGBA {
+	rgba := image.NewRGBA(image.Rect(0, 0, img.Bounds().Dx(), img.Bounds().Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, img.Bounds().Min, draw.Src)
+	return rgba
+}
@@ -0,0 +1,128 @@
+package screenstate
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrUnsupported 当前平台或环境无法获取键鼠空闲时长
+var ErrUnsupported = errors.New("input idle time not supported on this platform")
+
+// State 屏幕状态
+type State struct {
+	Locked             bool // 会话已锁定
+	ScreensaverRunning bool // 屏保正在运行
+	Idle               bool // 会话空闲（如 Linux logind 的 IdleHint，通常意味着已黑屏）
+}
+
+// Active 屏幕是否处于活跃状态（未锁定、未运行屏保、会话未空闲）
+func (s State) Active() bool {
+	return !s.Locked && !s.ScreensaverRunning && !s.Idle
+}
+
+// Detector 屏幕状态检测，测试或模拟运行时可替换为 Fake
+type Detector interface {
+	State() State
+	InputIdle() (time.Duration, error) // 距上次键盘或鼠标操作的时长
+}
+
+var (
+	mu       sync.RWMutex
+	detector Detector = systemDetector{}
+)
+
+// SetDetector 替换屏幕状态检测，传入 nil 时恢复为系统检测
+func SetDetector(d Detector) {
+	mu.Lock()
+	defer mu.Unlock()
+	if d == nil {
+		d = systemDetector{}
+	}
+	detector = d
+}
+
+// Current 获取当前屏幕状态
+func Current() State {
+	mu.RLock()
+	d := detector
+	mu.RUnlock()
+	return d.State()
+}
+
+// InputIdle 获取距上次键盘或鼠标操作的时长，不支持时返回 ErrUnsupported
+func InputIdle() (time.Duration, error) {
+	mu.RLock()
+	d := detector
+	mu.RUnlock()
+	return d.InputIdle()
+}
+
+// IsScreenLocked 检测屏幕是否被锁定
+func IsScreenLocked() bool {
+	return Current().Locked
+}
+
+// IsScreensaverRunning 检测屏幕保护程序是否正在运行
+func IsScreensaverRunning() bool {
+	return Current().ScreensaverRunning
+}
+
+// IsScreenActive 检测屏幕是否处于活跃状态（未锁定、未运行屏保）
+func IsScreenActive() bool {
+	return Current().Active()
+}
+
+// GetScreenStateInfo 获取屏幕状态详细信息（用于日志记录）
+func GetScreenStateInfo() (active bool, screensaverRunning bool, screenLocked bool) {
+	s := Current()
+	return s.Active(), s.ScreensaverRunning, s.Locked
+}
+
+// Fake 可手动设置的屏幕状态
+type Fake struct {
+	mu        sync.RWMutex
+	state     State
+	inputIdle time.Duration
+	idleErr   error
+}
+
+// NewFake 创建处于指定状态的 Fake
+func NewFake(state State) *Fake {
+	return &Fake{state: state}
+}
+
+// State 返回当前设置的状态
+func (f *Fake) State() State {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.state
+}
+
+// Set 修改屏幕状态
+func (f *Fake) Set(state State) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.state = state
+}
+
+// InputIdle 返回当前设置的键鼠空闲时长
+func (f *Fake) InputIdle() (time.Duration, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.inputIdle, f.idleErr
+}
+
+// SetInputIdle 修改键鼠空闲时长
+func (f *Fake) SetInputIdle(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.inputIdle = d
+}
+
+// SetInputIdleError 设置获取空闲时长时返回的错误，传入 nil 恢复正常
+func (f *Fake) SetInputIdleError(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.idleErr = err
+}
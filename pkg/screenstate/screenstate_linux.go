@@ -0,0 +1,155 @@
+//go:build linux
+// +build linux
+
+package screenstate
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/jezek/xgb"
+	"github.com/jezek/xgb/screensaver"
+	"github.com/jezek/xgb/xproto"
+)
+
+const (
+	logindService  = "org.freedesktop.login1"
+	logindManager  = "/org/freedesktop/login1"
+	logindSession  = "org.freedesktop.login1.Session"
+	logindAutoPath = "/org/freedesktop/login1/session/auto"
+	logindGetByPID = "org.freedesktop.login1.Manager.GetSessionByPID"
+	propLockedHint = logindSession + ".LockedHint"
+	propIdleHint   = logindSession + ".IdleHint"
+)
+
+// linux 到 logind（系统 D-Bus）与 X 服务器的长连接，出错后在下次检测时重连
+var linux struct {
+	mu sync.Mutex
+
+	bus     *dbus.Conn
+	session dbus.BusObject
+
+	x    *xgb.Conn
+	root xproto.Window
+}
+
+// systemDetector 通过 logind 的 LockedHint/IdleHint 与 X11 屏保扩展检测屏幕状态
+// 无法连接时按活跃处理，与其他平台保持一致
+type systemDetector struct{}
+
+func (systemDetector) State() State {
+	linux.mu.Lock()
+	defer linux.mu.Unlock()
+
+	var s State
+	s.Locked, s.Idle = logindHints()
+	s.ScreensaverRunning = x11ScreensaverOn()
+	return s
+}
+
+// logindHints 读取当前会话的 LockedHint 与 IdleHint
+func logindHints() (locked, idle bool) {
+	session := logindSessionObject()
+	if session == nil {
+		return false, false
+	}
+
+	lockedVar, err := session.GetProperty(propLockedHint)
+	if err != nil {
+		resetLogind()
+		return false, false
+	}
+	locked, _ = lockedVar.Value().(bool)
+
+	if idleVar, err := session.GetProperty(propIdleHint); err == nil {
+		idle, _ = idleVar.Value().(bool)
+	}
+	return locked, idle
+}
+
+// logindSessionObject 获取当前进程所属的 logind 会话
+func logindSessionObject() dbus.BusObject {
+	if linux.session != nil {
+		return linux.session
+	}
+
+	if linux.bus == nil {
+		bus, err := dbus.ConnectSystemBus()
+		if err != nil {
+			return nil
+		}
+		linux.bus = bus
+	}
+
+	// 优先按 PID 查找会话，找不到时（如从服务中启动）使用用户的图形会话
+	path := dbus.ObjectPath(logindAutoPath)
+	var byPID dbus.ObjectPath
+	err := linux.bus.Object(logindService, logindManager).Call(logindGetByPID, 0, uint32(os.Getpid())).Store(&byPID)
+	if err == nil && byPID.IsValid() {
+		path = byPID
+	}
+
+	linux.session = linux.bus.Object(logindService, path)
+	return linux.session
+}
+
+// resetLogind 关闭 D-Bus 连接，下次检测时重连
+func resetLogind() {
+	if linux.bus != nil {
+		linux.bus.Close()
+	}
+	linux.bus = nil
+	linux.session = nil
+}
+
+// InputIdle 通过 MIT-SCREEN-SAVER 扩展获取距上次键鼠操作的时长
+// Wayland 会话或没有 X 服务器时返回 ErrUnsupported
+func (systemDetector) InputIdle() (time.Duration, error) {
+	linux.mu.Lock()
+	defer linux.mu.Unlock()
+
+	info, err := x11ScreensaverInfo()
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(info.MsSinceUserInput) * time.Millisecond, nil
+}
+
+// x11ScreensaverOn 通过 MIT-SCREEN-SAVER 扩展判断屏保是否激活
+func x11ScreensaverOn() bool {
+	info, err := x11ScreensaverInfo()
+	if err != nil {
+		return false
+	}
+	return info.State == screensaver.StateOn
+}
+
+// x11ScreensaverInfo 查询 X 服务器的屏保状态与键鼠空闲时长
+func x11ScreensaverInfo() (*screensaver.QueryInfoReply, error) {
+	if linux.x == nil {
+		if os.Getenv("DISPLAY") == "" {
+			return nil, ErrUnsupported
+		}
+		conn, err := xgb.NewConn()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+		}
+		if err := screensaver.Init(conn); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+		}
+		linux.x = conn
+		linux.root = xproto.Setup(conn).DefaultScreen(conn).Root
+	}
+
+	info, err := screensaver.QueryInfo(linux.x, xproto.Drawable(linux.root)).Reply()
+	if err != nil {
+		linux.x.Close()
+		linux.x = nil
+		return nil, fmt.Errorf("failed to query screensaver info: %w", err)
+	}
+	return info, nil
+}
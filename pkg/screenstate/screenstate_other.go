@@ -1,22 +1,17 @@
-//go:build !windows
-// +build !windows
+//go:build !windows && !linux
+// +build !windows,!linux
 
 package screenstate
 
-// IsScreenLocked 检测屏幕是否被锁定（非Windows平台暂不支持）
-func IsScreenLocked() bool {
-	// 非Windows平台暂不检测，假设未锁定
-	return false
-}
+import "time"
+
+// systemDetector 该平台暂不支持检测，始终视为活跃
+type systemDetector struct{}
 
-// IsScreensaverRunning 检测屏幕保护程序是否正在运行（非Windows平台暂不支持）
-func IsScreensaverRunning() bool {
-	// 非Windows平台暂不检测，假设未运行
-	return false
+func (systemDetector) State() State {
+	return State{}
 }
 
-// IsScreenActive 检测屏幕是否处于活跃状态（非Windows平台默认为活跃）
-func IsScreenActive() bool {
-	// 非Windows平台默认为活跃
-	return true
+func (systemDetector) InputIdle() (time.Duration, error) {
+	return 0, ErrUnsupported
 }
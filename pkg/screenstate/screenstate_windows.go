@@ -4,18 +4,23 @@
 package screenstate
 
 import (
+	"fmt"
 	"syscall"
+	"time"
 	"unsafe"
 )
 
 var (
 	user32                   = syscall.NewLazyDLL("user32.dll")
 	wtsapi32                 = syscall.NewLazyDLL("wtsapi32.dll")
+	kernel32                 = syscall.NewLazyDLL("kernel32.dll")
 	procSystemParametersInfo = user32.NewProc("SystemParametersInfoW")
 	procGetForegroundWindow  = user32.NewProc("GetForegroundWindow")
 	procGetClassNameW        = user32.NewProc("GetClassNameW")
 	procWTSQuerySessionInfo  = wtsapi32.NewProc("WTSQuerySessionInformationW")
 	procWTSFreeMemory        = wtsapi32.NewProc("WTSFreeMemory")
+	procGetLastInputInfo     = user32.NewProc("GetLastInputInfo")
+	procGetTickCount         = kernel32.NewProc("GetTickCount")
 )
 
 const (
@@ -25,9 +30,38 @@ const (
 	WTSSessionInfoEx          = 25
 )
 
-// IsScreenLocked 检测屏幕是否被锁定
+// systemDetector 通过 Win32 API 检测锁屏与屏保
+type systemDetector struct{}
+
+func (systemDetector) State() State {
+	return State{
+		Locked:             screenLocked(),
+		ScreensaverRunning: screensaverRunning(),
+	}
+}
+
+// lastInputInfo 对应 Win32 LASTINPUTINFO
+type lastInputInfo struct {
+	cbSize uint32
+	dwTime uint32
+}
+
+// InputIdle 通过 GetLastInputInfo 获取距上次键鼠操作的时长
+func (systemDetector) InputIdle() (time.Duration, error) {
+	info := lastInputInfo{cbSize: uint32(unsafe.Sizeof(lastInputInfo{}))}
+	ret, _, err := procGetLastInputInfo.Call(uintptr(unsafe.Pointer(&info)))
+	if ret == 0 {
+		return 0, fmt.Errorf("GetLastInputInfo failed: %w", err)
+	}
+
+	// 两者都是开机以来的毫秒数（约 49.7 天回绕），按无符号相减即可跨越回绕
+	tick, _, _ := procGetTickCount.Call()
+	return time.Duration(uint32(tick)-info.dwTime) * time.Millisecond, nil
+}
+
+// screenLocked 检测屏幕是否被锁定
 // 使用多种方法综合判断以提高准确性
-func IsScreenLocked() bool {
+func screenLocked() bool {
 	// 方法1：检查前台窗口的类名
 	hwnd, _, _ := procGetForegroundWindow.Call()
 	if hwnd != 0 {
@@ -69,8 +103,8 @@ func IsScreenLocked() bool {
 	return false
 }
 
-// IsScreensaverRunning 检测屏幕保护程序是否正在运行
-func IsScreensaverRunning() bool {
+// screensaverRunning 检测屏幕保护程序是否正在运行
+func screensaverRunning() bool {
 	var running uint32
 	ret, _, _ := procSystemParametersInfo.Call(
 		uintptr(SPI_GETSCREENSAVERRUNNING),
@@ -87,29 +121,3 @@ func IsScreensaverRunning() bool {
 	isRunning := running != 0
 	return isRunning
 }
-
-// IsScreenActive 检测屏幕是否处于活跃状态（未锁定、未运行屏保）
-func IsScreenActive() bool {
-	screensaverRunning := IsScreensaverRunning()
-	screenLocked := IsScreenLocked()
-	
-	// 如果屏保正在运行，屏幕不活跃
-	if screensaverRunning {
-		return false
-	}
-	
-	// 如果屏幕被锁定，屏幕不活跃
-	if screenLocked {
-		return false
-	}
-	
-	return true
-}
-
-// GetScreenStateInfo 获取屏幕状态详细信息（用于日志记录）
-func GetScreenStateInfo() (active bool, screensaverRunning bool, screenLocked bool) {
-	screensaverRunning = IsScreensaverRunning()
-	screenLocked = IsScreenLocked()
-	active = !screensaverRunning && !screenLocked
-	return
-}
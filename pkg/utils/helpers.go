@@ -5,11 +5,13 @@ import (
 	"encoding/hex"
 	"fmt"
 	"time"
+
+	"WorkTrackerAI/pkg/clock"
 )
 
 // TimeInRange 检查当前时间是否在指定范围内
 func TimeInRange(startTime, endTime string) (bool, error) {
-	now := time.Now()
+	now := clock.Now()
 
 	start, err := time.Parse("15:04", startTime)
 	if err != nil {
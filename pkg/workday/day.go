@@ -0,0 +1,41 @@
+package workday
+
+import (
+	"time"
+
+	"WorkTrackerAI/pkg/models"
+)
+
+// dayBoundary 返回逻辑日起点相对零点的偏移（未配置或格式无效时为零点）
+func dayBoundary(schedule models.WorkSchedule) time.Duration {
+	t, err := time.Parse("15:04", schedule.DayBoundary)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+}
+
+// LogicalDay 返回 t 所属的逻辑日（该日零点）
+// 逻辑日从配置的 DayBoundary 开始，例如分界为 06:00 时，凌晨 02:00 属于前一天。
+func LogicalDay(schedule models.WorkSchedule, t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	if t.Before(day.Add(dayBoundary(schedule))) {
+		day = day.AddDate(0, 0, -1)
+	}
+	return day
+}
+
+// DayRange 返回逻辑日 date 的时间范围 [当天分界, 次日分界)
+func DayRange(schedule models.WorkSchedule, date time.Time) Range {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	boundary := dayBoundary(schedule)
+	return Range{
+		Start: day.Add(boundary),
+		End:   day.AddDate(0, 0, 1).Add(boundary),
+	}
+}
+
+// Today 返回当前时刻所属逻辑日的时间范围
+func Today(schedule models.WorkSchedule, now time.Time) Range {
+	return DayRange(schedule, LogicalDay(schedule, now))
+}
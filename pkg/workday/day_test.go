@@ -0,0 +1,55 @@
+package workday
+
+import (
+	"testing"
+	"time"
+
+	"WorkTrackerAI/pkg/models"
+)
+
+func TestLogicalDay(t *testing.T) {
+	tests := []struct {
+		name     string
+		boundary string
+		t        time.Time
+		want     time.Time
+	}{
+		{"未配置分界按零点", "", at(1, 2, 0), at(1, 0, 0)},
+		{"分界前属于前一天", "06:00", at(1, 2, 0), at(0, 0, 0)},
+		{"正好在分界上属于当天", "06:00", at(1, 6, 0), at(1, 0, 0)},
+		{"分界后属于当天", "06:00", at(1, 23, 59), at(1, 0, 0)},
+		{"无效分界按零点", "25:99", at(1, 2, 0), at(1, 0, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := LogicalDay(models.WorkSchedule{DayBoundary: tt.boundary}, tt.t)
+			if !got.Equal(tt.want) {
+				t.Errorf("LogicalDay(%s) = %s, want %s", tt.t.Format("01-02 15:04"),
+					got.Format("01-02 15:04"), tt.want.Format("01-02 15:04"))
+			}
+		})
+	}
+}
+
+func TestToday(t *testing.T) {
+	tests := []struct {
+		name     string
+		boundary string
+		now      time.Time
+		want     Range
+	}{
+		{"零点分界", "", at(1, 2, 0), Range{at(1, 0, 0), at(2, 0, 0)}},
+		{"凌晨属于前一逻辑日", "06:00", at(1, 5, 59), Range{at(0, 6, 0), at(1, 6, 0)}},
+		{"分界后开始新的逻辑日", "06:00", at(1, 6, 0), Range{at(1, 6, 0), at(2, 6, 0)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Today(models.WorkSchedule{DayBoundary: tt.boundary}, tt.now)
+			if !sameRange(got, tt.want) {
+				t.Errorf("Today(%s) = %v, want %v", tt.now.Format("01-02 15:04"), got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package workday
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"WorkTrackerAI/pkg/models"
+)
+
+// Calendar 节假日日历，返回某天是放假还是调休上班
+type Calendar interface {
+	HolidayKind(date time.Time) (kind string, ok bool)
+}
+
+var (
+	calendarMu sync.RWMutex
+	calendar   Calendar
+)
+
+// SetCalendar 设置全局节假日日历，工作日判断会优先使用日历中的放假/调休安排
+func SetCalendar(c Calendar) {
+	calendarMu.Lock()
+	defer calendarMu.Unlock()
+	calendar = c
+}
+
+// holidayKind 查询全局节假日日历
+func holidayKind(date time.Time) (string, bool) {
+	calendarMu.RLock()
+	c := calendar
+	calendarMu.RUnlock()
+	if c == nil {
+		return "", false
+	}
+	return c.HolidayKind(date)
+}
+
+// ParseHolidays 解析节假日文件，自动识别 ICS 与 JSON 格式
+func ParseHolidays(data []byte) ([]*models.Holiday, error) {
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("BEGIN:VCALENDAR")) {
+		return ParseICS(trimmed)
+	}
+	return ParseHolidayJSON(trimmed)
+}
+
+// ParseHolidayJSON 解析 JSON 格式的节假日列表
+// 格式: [{"date": "2026-10-01", "name": "国庆节", "kind": "holiday"}, {"date": "2026-10-11", "name": "国庆节调休", "kind": "workday"}]
+// 也可以用 start/end 表示连续多天（包含两端）
+func ParseHolidayJSON(data []byte) ([]*models.Holiday, error) {
+	var entries []struct {
+		Date  string `json:"date"`
+		Start string `json:"start"`
+		End   string `json:"end"`
+		Name  string `json:"name"`
+		Kind  string `json:"kind"`
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("解析节假日 JSON 失败: %w", err)
+	}
+
+	var holidays []*models.Holiday
+	for _, e := range entries {
+		kind := e.Kind
+		if kind == "" {
+			kind = guessHolidayKind(e.Name)
+		}
+		if kind != models.HolidayKindOff && kind != models.HolidayKindWorkday {
+			return nil, fmt.Errorf("无效的节假日类型: %s", kind)
+		}
+
+		start, end := e.Start, e.End
+		if e.Date != "" {
+			start, end = e.Date, e.Date
+		}
+		if end == "" {
+			end = start
+		}
+		days, err := expandDates(start, end, true)
+		if err != nil {
+			return nil, err
+		}
+		for _, day := range days {
+			holidays = append(holidays, &models.Holiday{Date: day, Name: e.Name, Kind: kind, Source: "json"})
+		}
+	}
+	return holidays, nil
+}
+
+// ParseICS 解析 ICS 日历中的全天事件
+// 事件标题包含 "班"（如 "国庆节补班"、"调休上班"）时视为调休上班，否则视为放假
+func ParseICS(data []byte) ([]*models.Holiday, error) {
+	var (
+		holidays []*models.Holiday
+		inEvent  bool
+		start    string
+		end      string
+		summary  string
+	)
+
+	for _, line := range unfoldICSLines(data) {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		// 去掉属性参数，如 DTSTART;VALUE=DATE
+		if i := strings.Index(name, ";"); i >= 0 {
+			name = name[:i]
+		}
+
+		switch strings.ToUpper(name) {
+		case "BEGIN":
+			if value == "VEVENT" {
+				inEvent, start, end, summary = true, "", "", ""
+			}
+		case "DTSTART":
+			start = value
+		case "DTEND":
+			end = value
+		case "SUMMARY":
+			summary = strings.ReplaceAll(value, `\,`, ",")
+		case "END":
+			if value != "VEVENT" || !inEvent {
+				continue
+			}
+			inEvent = false
+			if len(start) < 8 {
+				continue
+			}
+
+			// ICS 的 DTEND 不包含当天；缺失时表示只有一天
+			inclusive := false
+			if len(end) < 8 {
+				end, inclusive = start, true
+			}
+			days, err := expandDates(start[:8], end[:8], inclusive)
+			if err != nil {
+				return nil, err
+			}
+			kind := guessHolidayKind(summary)
+			for _, day := range days {
+				holidays = append(holidays, &models.Holiday{Date: day, Name: summary, Kind: kind, Source: "ics"})
+			}
+		}
+	}
+	return holidays, nil
+}
+
+// unfoldICSLines 按行拆分 ICS 内容，并合并以空格或制表符开头的续行
+func unfoldICSLines(data []byte) []string {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// guessHolidayKind 根据名称判断是放假还是调休上班
+func guessHolidayKind(name string) string {
+	if strings.Contains(name, "班") {
+		return models.HolidayKindWorkday
+	}
+	return models.HolidayKindOff
+}
+
+// expandDates 展开日期范围，支持 "2006-01-02" 与 "20060102" 两种格式
+func expandDates(from, to string, inclusive bool) ([]string, error) {
+	start, err := parseDate(from)
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseDate(to)
+	if err != nil {
+		return nil, err
+	}
+	if inclusive {
+		end = end.AddDate(0, 0, 1)
+	}
+	if end.Sub(start) > 366*24*time.Hour {
+		return nil, fmt.Errorf("日期范围过长: %s - %s", from, to)
+	}
+
+	var days []string
+	for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+		days = append(days, d.Format("2006-01-02"))
+	}
+	return days, nil
+}
+
+// parseDate 解析日期
+func parseDate(s string) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02", "20060102"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("无效的日期: %s", s)
+}
@@ -0,0 +1,106 @@
+package workday
+
+import (
+	"time"
+
+	"WorkTrackerAI/pkg/models"
+)
+
+// 分析时间段对齐方式
+const (
+	AlignClock     = "clock"      // 从逻辑日分界（默认零点）开始按时长对齐（如 09:00、09:30）
+	AlignWorkStart = "work_start" // 从工作开始时间按时长对齐（如 08:45、09:45）
+)
+
+// 默认分析时间段长度（分钟）
+const DefaultSegmentMinutes = 60
+
+// Range 时间段 [Start, End)
+type Range struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Segmenter 按配置的时长与对齐方式切分分析时间段
+// 时间段不会跨越每天的对齐起点，时长不能整除一天时当天最后一段会被截短。
+type Segmenter struct {
+	length time.Duration
+	offset time.Duration // 对齐起点相对零点的偏移
+}
+
+// NewSegmenter 根据工作时间配置创建切分器
+func NewSegmenter(schedule models.WorkSchedule) Segmenter {
+	minutes := schedule.SegmentMinutes
+	if minutes <= 0 || minutes > 24*60 {
+		minutes = DefaultSegmentMinutes
+	}
+
+	// 按整点对齐时从逻辑日分界开始切分（默认零点）
+	offset := dayBoundary(schedule)
+	if schedule.SegmentAlignment == AlignWorkStart {
+		// 配置了多个工作时段时，按最早的时段开始时间对齐
+		startTime := schedule.StartTime
+		for i, w := range schedule.Windows {
+			if i == 0 || w.Start < startTime {
+				startTime = w.Start
+			}
+		}
+		if t, err := time.Parse("15:04", startTime); err == nil {
+			offset = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+		}
+	}
+
+	return Segmenter{
+		length: time.Duration(minutes) * time.Minute,
+		offset: offset,
+	}
+}
+
+// Length 时间段长度
+func (s Segmenter) Length() time.Duration {
+	return s.length
+}
+
+// anchor 返回 t 所在对齐周期的起点与下一个起点
+func (s Segmenter) anchor(t time.Time) (time.Time, time.Time) {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	a := day.Add(s.offset)
+	if t.Before(a) {
+		a = day.AddDate(0, 0, -1).Add(s.offset)
+	}
+	next := time.Date(a.Year(), a.Month(), a.Day()+1, 0, 0, 0, 0, a.Location()).Add(s.offset)
+	return a, next
+}
+
+// Segment 返回包含 t 的时间段
+func (s Segmenter) Segment(t time.Time) Range {
+	a, next := s.anchor(t)
+	k := t.Sub(a) / s.length
+	start := a.Add(k * s.length)
+	end := start.Add(s.length)
+	if end.After(next) {
+		end = next
+	}
+	return Range{Start: start, End: end}
+}
+
+// Previous 返回 now 之前最近一个已结束的时间段
+func (s Segmenter) Previous(now time.Time) Range {
+	current := s.Segment(now)
+	return s.Segment(current.Start.Add(-time.Nanosecond))
+}
+
+// Split 将 [from, to) 按时间段边界切分，首尾两段会被截取到 from/to
+func (s Segmenter) Split(from, to time.Time) []Range {
+	var ranges []Range
+	for start := from; start.Before(to); {
+		seg := s.Segment(start)
+		end := seg.End
+		if end.After(to) {
+			end = to
+		}
+		ranges = append(ranges, Range{Start: start, End: end})
+		start = end
+	}
+	return ranges
+}
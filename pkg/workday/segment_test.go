@@ -0,0 +1,156 @@
+package workday
+
+import (
+	"testing"
+	"time"
+
+	"WorkTrackerAI/pkg/models"
+)
+
+// at 返回 2026-03-02（周一）起第 day 天的 hh:mm（UTC）
+func at(day, hour, minute int) time.Time {
+	return time.Date(2026, 3, 2+day, hour, minute, 0, 0, time.UTC)
+}
+
+func sameRange(a, b Range) bool {
+	return a.Start.Equal(b.Start) && a.End.Equal(b.End)
+}
+
+func TestSegment(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule models.WorkSchedule
+		t        time.Time
+		want     Range
+	}{
+		{
+			name:     "按整点对齐",
+			schedule: models.WorkSchedule{SegmentMinutes: 60},
+			t:        at(0, 9, 20),
+			want:     Range{at(0, 9, 0), at(0, 10, 0)},
+		},
+		{
+			name:     "未配置时长使用默认值",
+			schedule: models.WorkSchedule{},
+			t:        at(0, 9, 59),
+			want:     Range{at(0, 9, 0), at(0, 10, 0)},
+		},
+		{
+			name:     "不能整除一天时最后一段截短到零点",
+			schedule: models.WorkSchedule{SegmentMinutes: 45},
+			t:        at(0, 23, 50),
+			want:     Range{at(0, 23, 15), at(1, 0, 0)},
+		},
+		{
+			name:     "按工作开始时间对齐",
+			schedule: models.WorkSchedule{SegmentMinutes: 60, SegmentAlignment: AlignWorkStart, StartTime: "08:45"},
+			t:        at(0, 9, 10),
+			want:     Range{at(0, 8, 45), at(0, 9, 45)},
+		},
+		{
+			name:     "早于对齐起点时属于前一天的周期",
+			schedule: models.WorkSchedule{SegmentMinutes: 60, SegmentAlignment: AlignWorkStart, StartTime: "08:45"},
+			t:        at(0, 8, 0),
+			want:     Range{at(0, 7, 45), at(0, 8, 45)},
+		},
+		{
+			name: "多个工作时段按最早开始时间对齐",
+			schedule: models.WorkSchedule{
+				SegmentMinutes:   60,
+				SegmentAlignment: AlignWorkStart,
+				Windows:          []models.TimeWindow{{Start: "13:30", End: "18:00"}, {Start: "08:30", End: "12:00"}},
+			},
+			t:    at(0, 9, 0),
+			want: Range{at(0, 8, 30), at(0, 9, 30)},
+		},
+		{
+			name:     "整点对齐从逻辑日分界开始",
+			schedule: models.WorkSchedule{SegmentMinutes: 90, DayBoundary: "06:00"},
+			t:        at(1, 5, 50),
+			want:     Range{at(1, 4, 30), at(1, 6, 0)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewSegmenter(tt.schedule).Segment(tt.t)
+			if !sameRange(got, tt.want) {
+				t.Errorf("Segment(%s) = [%s, %s), want [%s, %s)", tt.t.Format("01-02 15:04"),
+					got.Start.Format("01-02 15:04"), got.End.Format("01-02 15:04"),
+					tt.want.Start.Format("01-02 15:04"), tt.want.End.Format("01-02 15:04"))
+			}
+		})
+	}
+}
+
+func TestPrevious(t *testing.T) {
+	tests := []struct {
+		name    string
+		minutes int
+		now     time.Time
+		want    Range
+	}{
+		{"上一个完整时间段", 30, at(0, 10, 5), Range{at(0, 9, 30), at(0, 10, 0)}},
+		{"正好在边界上", 30, at(0, 10, 0), Range{at(0, 9, 30), at(0, 10, 0)}},
+		{"跨过零点取前一天被截短的最后一段", 45, at(1, 0, 10), Range{at(0, 23, 15), at(1, 0, 0)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewSegmenter(models.WorkSchedule{SegmentMinutes: tt.minutes}).Previous(tt.now)
+			if !sameRange(got, tt.want) {
+				t.Errorf("Previous(%s) = %v, want %v", tt.now.Format("01-02 15:04"), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplit(t *testing.T) {
+	s := NewSegmenter(models.WorkSchedule{SegmentMinutes: 30})
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     []Range
+	}{
+		{
+			name: "首尾截取到范围内",
+			from: at(0, 9, 10),
+			to:   at(0, 10, 20),
+			want: []Range{
+				{at(0, 9, 10), at(0, 9, 30)},
+				{at(0, 9, 30), at(0, 10, 0)},
+				{at(0, 10, 0), at(0, 10, 20)},
+			},
+		},
+		{
+			name: "与边界对齐",
+			from: at(0, 23, 30),
+			to:   at(1, 0, 30),
+			want: []Range{
+				{at(0, 23, 30), at(1, 0, 0)},
+				{at(1, 0, 0), at(1, 0, 30)},
+			},
+		},
+		{
+			name: "空范围",
+			from: at(0, 9, 0),
+			to:   at(0, 9, 0),
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := s.Split(tt.from, tt.to)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Split returned %d ranges, want %d: %v", len(got), len(tt.want), got)
+			}
+			for i := range got {
+				if !sameRange(got[i], tt.want[i]) {
+					t.Errorf("range %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package workday
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"WorkTrackerAI/pkg/models"
+)
+
+// WindowsForWeekday 返回某星期几配置的工作时段定义（不判断是否为工作日）
+// 优先使用按星期几的覆盖配置，其次是通用时段列表，都未配置时使用 StartTime-EndTime。
+func WindowsForWeekday(schedule models.WorkSchedule, weekday time.Weekday) []models.TimeWindow {
+	if windows, ok := schedule.WeekdayWindows[int(weekday)]; ok {
+		return windows
+	}
+	return DefaultWindows(schedule)
+}
+
+// DefaultWindows 返回通用工作时段定义（未按星期几覆盖时使用）
+func DefaultWindows(schedule models.WorkSchedule) []models.TimeWindow {
+	if len(schedule.Windows) > 0 {
+		return schedule.Windows
+	}
+	return []models.TimeWindow{{Start: schedule.StartTime, End: schedule.EndTime}}
+}
+
+// IsWorkday 判断 date 是否为工作日
+// 节假日日历优先：放假日不工作，调休上班日工作；
+// 不在日历中时按配置的工作日判断（未配置工作日时视为每天都工作）。
+func IsWorkday(schedule models.WorkSchedule, date time.Time) bool {
+	if kind, ok := holidayKind(date); ok {
+		return kind == models.HolidayKindWorkday
+	}
+	if len(schedule.WorkDays) == 0 {
+		return true
+	}
+	for _, day := range schedule.WorkDays {
+		if int(date.Weekday()) == day {
+			return true
+		}
+	}
+	return false
+}
+
+// Windows 返回逻辑日 date 的工作时段（按开始时间排序），非工作日返回 nil
+// 调休上班日如果该星期几没有配置时段（如周六），使用通用工作时段。
+// 跨越午夜的时段（如 22:00-06:00）结束于次日；早于逻辑日分界开始的时段属于次日凌晨。
+func Windows(schedule models.WorkSchedule, date time.Time) []Range {
+	if !IsWorkday(schedule, date) {
+		return nil
+	}
+
+	defs := WindowsForWeekday(schedule, date.Weekday())
+	if len(defs) == 0 {
+		if kind, ok := holidayKind(date); ok && kind == models.HolidayKindWorkday {
+			defs = DefaultWindows(schedule)
+		}
+	}
+	return rangesOn(date, dayBoundary(schedule), defs)
+}
+
+// WindowsAround 返回 t 所属逻辑日及前一逻辑日的工作时段（用于判断跨午夜时段）
+func WindowsAround(schedule models.WorkSchedule, t time.Time) []Range {
+	day := LogicalDay(schedule, t)
+	return append(Windows(schedule, day.AddDate(0, 0, -1)), Windows(schedule, day)...)
+}
+
+// RangesOn 将时段定义应用到 date 当天（按开始时间排序，忽略无效时段）
+func RangesOn(date time.Time, defs []models.TimeWindow) []Range {
+	return rangesOn(date, 0, defs)
+}
+
+// rangesOn 将时段定义应用到逻辑日 date，boundary 为逻辑日分界相对零点的偏移
+func rangesOn(date time.Time, boundary time.Duration, defs []models.TimeWindow) []Range {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	var ranges []Range
+	for _, w := range defs {
+		start, err1 := clockOn(day, w.Start)
+		end, err2 := clockOn(day, w.End)
+		if err1 != nil || err2 != nil || w.Start == w.End {
+			continue
+		}
+		// 结束时间早于开始时间表示跨越午夜
+		if !end.After(start) {
+			end = end.AddDate(0, 0, 1)
+		}
+		// 早于逻辑日分界开始的时段属于次日凌晨
+		if start.Before(day.Add(boundary)) {
+			start, end = start.AddDate(0, 0, 1), end.AddDate(0, 0, 1)
+		}
+		ranges = append(ranges, Range{Start: start, End: end})
+	}
+
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].Start.Before(ranges[j].Start) })
+	return ranges
+}
+
+// DayBounds 返回逻辑日 date 第一个工作时段的开始与最后一个工作时段的结束
+func DayBounds(schedule models.WorkSchedule, date time.Time) (Range, bool) {
+	windows := Windows(schedule, date)
+	if len(windows) == 0 {
+		return Range{}, false
+	}
+
+	bounds := windows[0]
+	for _, w := range windows[1:] {
+		if w.End.After(bounds.End) {
+			bounds.End = w.End
+		}
+	}
+	return bounds, true
+}
+
+// InWorkTime 判断时间点 t 是否在某个工作时段内
+func InWorkTime(schedule models.WorkSchedule, t time.Time) bool {
+	for _, w := range WindowsAround(schedule, t) {
+		if !t.Before(w.Start) && t.Before(w.End) {
+			return true
+		}
+	}
+	return false
+}
+
+// OverlapsWorkTime 判断时间段 [start, end) 是否与某个工作时段重叠
+func OverlapsWorkTime(schedule models.WorkSchedule, start, end time.Time) bool {
+	last := LogicalDay(schedule, end.Add(-time.Nanosecond))
+	for day := LogicalDay(schedule, start).AddDate(0, 0, -1); !day.After(last); day = day.AddDate(0, 0, 1) {
+		for _, w := range Windows(schedule, day) {
+			if start.Before(w.End) && w.Start.Before(end) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ValidateSchedule 校验工作时间配置
+func ValidateSchedule(schedule models.WorkSchedule) error {
+	if m := schedule.SegmentMinutes; m < 5 || m > 24*60 {
+		return fmt.Errorf("分析时间段长度必须在 5 到 1440 分钟之间")
+	}
+	if a := schedule.SegmentAlignment; a != AlignClock && a != AlignWorkStart {
+		return fmt.Errorf("无效的时间段对齐方式: %s", a)
+	}
+
+	if schedule.DayBoundary != "" {
+		if _, err := time.Parse("15:04", schedule.DayBoundary); err != nil {
+			return fmt.Errorf("无效的逻辑日分界时间: %s", schedule.DayBoundary)
+		}
+	}
+
+	t := schedule.Triggers
+	if t.ScreenshotCount < 0 || t.IdleMinutes < 0 || t.MinInterval < 0 {
+		return fmt.Errorf("事件触发分析的参数不能为负数")
+	}
+	if t.ActivityChange < 0 || t.ActivityChange > 1 {
+		return fmt.Errorf("画面变化阈值必须在 0 到 1 之间")
+	}
+
+	check := func(windows []models.TimeWindow) error {
+		for _, w := range windows {
+			start, err := time.Parse("15:04", w.Start)
+			if err != nil {
+				return fmt.Errorf("无效的工作时段开始时间: %s", w.Start)
+			}
+			end, err := time.Parse("15:04", w.End)
+			if err != nil {
+				return fmt.Errorf("无效的工作时段结束时间: %s", w.End)
+			}
+			if end.Equal(start) {
+				return fmt.Errorf("工作时段 %s-%s 的开始时间与结束时间不能相同", w.Start, w.End)
+			}
+		}
+		return nil
+	}
+
+	if err := check(schedule.Windows); err != nil {
+		return err
+	}
+	for weekday, windows := range schedule.WeekdayWindows {
+		if weekday < 0 || weekday > 6 {
+			return fmt.Errorf("无效的星期: %d", weekday)
+		}
+		if err := check(windows); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// clockOn 将 "15:04" 格式的时间应用到 day 当天
+func clockOn(day time.Time, clock string) (time.Time, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), 0, 0, day.Location()), nil
+}
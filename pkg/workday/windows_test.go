@@ -0,0 +1,134 @@
+package workday
+
+import (
+	"testing"
+	"time"
+
+	"WorkTrackerAI/pkg/models"
+)
+
+var weekdays = []int{1, 2, 3, 4, 5}
+
+func TestWindows(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule models.WorkSchedule
+		date     time.Time
+		want     []Range
+	}{
+		{
+			name:     "未配置时段使用开始结束时间",
+			schedule: models.WorkSchedule{StartTime: "09:00", EndTime: "18:00"},
+			date:     at(0, 0, 0),
+			want:     []Range{{at(0, 9, 0), at(0, 18, 0)}},
+		},
+		{
+			name: "多个时段按开始时间排序",
+			schedule: models.WorkSchedule{Windows: []models.TimeWindow{
+				{Start: "13:00", End: "18:00"},
+				{Start: "09:00", End: "12:00"},
+			}},
+			date: at(0, 0, 0),
+			want: []Range{{at(0, 9, 0), at(0, 12, 0)}, {at(0, 13, 0), at(0, 18, 0)}},
+		},
+		{
+			name: "跨越午夜的时段结束于次日",
+			schedule: models.WorkSchedule{Windows: []models.TimeWindow{
+				{Start: "22:00", End: "06:00"},
+			}},
+			date: at(0, 0, 0),
+			want: []Range{{at(0, 22, 0), at(1, 6, 0)}},
+		},
+		{
+			name: "早于逻辑日分界开始的时段属于次日凌晨",
+			schedule: models.WorkSchedule{DayBoundary: "06:00", Windows: []models.TimeWindow{
+				{Start: "20:00", End: "23:59"},
+				{Start: "00:00", End: "04:00"},
+			}},
+			date: at(0, 0, 0),
+			want: []Range{{at(0, 20, 0), at(0, 23, 59)}, {at(1, 0, 0), at(1, 4, 0)}},
+		},
+		{
+			name: "忽略无效时段",
+			schedule: models.WorkSchedule{Windows: []models.TimeWindow{
+				{Start: "9am", End: "12:00"},
+				{Start: "10:00", End: "10:00"},
+				{Start: "14:00", End: "15:00"},
+			}},
+			date: at(0, 0, 0),
+			want: []Range{{at(0, 14, 0), at(0, 15, 0)}},
+		},
+		{
+			name: "按星期几覆盖时段",
+			schedule: models.WorkSchedule{
+				StartTime:      "09:00",
+				EndTime:        "18:00",
+				WeekdayWindows: map[int][]models.TimeWindow{1: {{Start: "10:00", End: "12:00"}}},
+			},
+			date: at(0, 0, 0),
+			want: []Range{{at(0, 10, 0), at(0, 12, 0)}},
+		},
+		{
+			name: "覆盖为空列表表示当天不工作",
+			schedule: models.WorkSchedule{
+				StartTime:      "09:00",
+				EndTime:        "18:00",
+				WeekdayWindows: map[int][]models.TimeWindow{1: {}},
+			},
+			date: at(0, 0, 0),
+			want: nil,
+		},
+		{
+			name:     "非工作日",
+			schedule: models.WorkSchedule{StartTime: "09:00", EndTime: "18:00", WorkDays: weekdays},
+			date:     at(-1, 0, 0),
+			want:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Windows(tt.schedule, tt.date)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Windows returned %d ranges, want %d: %v", len(got), len(tt.want), got)
+			}
+			for i := range got {
+				if !sameRange(got[i], tt.want[i]) {
+					t.Errorf("window %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestInWorkTime(t *testing.T) {
+	night := models.WorkSchedule{
+		WorkDays: weekdays,
+		Windows:  []models.TimeWindow{{Start: "22:00", End: "06:00"}},
+	}
+	nightBoundary := night
+	nightBoundary.DayBoundary = "12:00"
+
+	tests := []struct {
+		name     string
+		schedule models.WorkSchedule
+		t        time.Time
+		want     bool
+	}{
+		{"夜班开始前", night, at(0, 21, 59), false},
+		{"夜班开始", night, at(0, 22, 0), true},
+		{"次日凌晨属于前一天的夜班", night, at(1, 2, 0), true},
+		{"夜班结束", night, at(1, 6, 0), false},
+		{"周五夜班延续到周六凌晨", night, at(5, 2, 0), true},
+		{"周一凌晨没有周日的夜班", night, at(0, 2, 0), false},
+		{"配置分界后凌晨仍在夜班内", nightBoundary, at(1, 2, 0), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InWorkTime(tt.schedule, tt.t); got != tt.want {
+				t.Errorf("InWorkTime(%s) = %v, want %v", tt.t.Format("Mon 15:04"), got, tt.want)
+			}
+		})
+	}
+}